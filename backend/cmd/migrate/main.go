@@ -2,41 +2,189 @@ package main
 
 import (
 	"flag"
-	"log"
+	"fmt"
+	"log/slog"
+	"os"
 	"strconv"
 
+	"github.com/GFerreiroS/guild-manager/backend/internal/config"
 	"github.com/GFerreiroS/guild-manager/backend/internal/database"
+	"github.com/GFerreiroS/guild-manager/backend/internal/logging"
 )
 
+const usage = `Usage: migrate [flags] <command> [args]
+
+Commands:
+  up                 apply all pending migrations
+  down [n|all]       revert the last n migrations (default 1), or all of them
+  goto <version>     migrate up or down to version
+  steps <n>          apply n migrations, or revert -n when negative
+  force <version>    set the version and clear the dirty flag without running SQL
+  status             show the current version, dirty flag and pending migrations
+  validate           check that every up migration has a down migration
+  create <name>      scaffold the next numbered up/down pair
+
+Flags:
+`
+
 func main() {
-	var action string
-	var version string
-	flag.StringVar(&action, "action", "up", "Migration action (up/down/force)")
-	flag.StringVar(&version, "version", "", "Version number for force action")
+	dryRun := flag.Bool("dry-run", false, "print the SQL that would be applied instead of running it")
+	dir := flag.String("dir", "internal/database/migrations", "migrations directory used by create")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	command, args := args[0], args[1:]
+
+	cfg, err := config.Load()
+	if err != nil {
+		logging.Fatal("failed to load config", "error", err)
+	}
+	if err := logging.Setup(cfg.Log.Level, cfg.Log.Format); err != nil {
+		logging.Fatal("failed to set up logging", "error", err)
+	}
+
+	// Commands that only look at the migration files.
+	switch command {
+	case "validate":
+		files, err := database.Migrations()
+		if err != nil {
+			logging.Fatal("validation failed", "error", err)
+		}
+		slog.Info("every migration has up and down files", "migrations", len(files))
+		return
+	case "create":
+		if len(args) != 1 {
+			logging.Fatal("create needs a migration name")
+		}
+		up, down, err := database.CreateMigration(*dir, args[0])
+		if err != nil {
+			logging.Fatal("create failed", "error", err)
+		}
+		slog.Info("created migration", "up", up, "down", down)
+		return
+	}
+
 	// Initialize database
-	db, err := database.NewPostgresDB()
+	db, err := database.NewPostgresDB(cfg.Database)
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		logging.Fatal("failed to connect to database", "database", cfg.Database, "error", err)
+	}
+	m, err := database.NewMigrator(db)
+	if err != nil {
+		logging.Fatal("failed to load migrations", "error", err)
 	}
 
-	// Handle migration action
-	switch action {
+	switch command {
 	case "up":
-		if err := database.RunMigrations(db); err != nil {
-			log.Fatalf("Migration failed: %v", err)
+		run(*dryRun, m.PlanUp, m.Up)
+	case "down":
+		n := 1
+		if len(args) > 0 && args[0] == "all" {
+			n = 0
+		} else if len(args) > 0 {
+			if n = atoi(args[0], "count"); n < 1 {
+				logging.Fatal("down needs a positive count or all")
+			}
+		}
+		run(*dryRun,
+			func() ([]database.MigrationStep, error) { return m.PlanDown(n) },
+			func() error { return m.Down(n) })
+	case "goto":
+		v := atoi(argument(args, "version"), "version")
+		if v < 1 {
+			logging.Fatal("goto needs a positive version")
 		}
+		version := uint(v)
+		run(*dryRun,
+			func() ([]database.MigrationStep, error) { return m.PlanGoto(version) },
+			func() error { return m.Goto(version) })
+	case "steps":
+		n := atoi(argument(args, "step count"), "step count")
+		run(*dryRun,
+			func() ([]database.MigrationStep, error) { return m.PlanSteps(n) },
+			func() error { return m.Steps(n) })
 	case "force":
-		ver, err := strconv.Atoi(version)
+		ver := atoi(argument(args, "version"), "version")
+		if err := m.Force(ver); err != nil {
+			logging.Fatal("force version failed", "error", err)
+		}
+		slog.Info("forced version", "version", ver)
+	case "status":
+		printStatus(m)
+	default:
+		logging.Fatal("invalid command", "command", command)
+	}
+}
+
+// run prints the planned SQL when dryRun is set and applies it otherwise.
+func run(dryRun bool, plan func() ([]database.MigrationStep, error), apply func() error) {
+	if dryRun {
+		steps, err := plan()
 		if err != nil {
-			log.Fatalf("Invalid version: %v", err)
+			logging.Fatal("planning failed", "error", err)
 		}
-		if err := database.ForceVersion(db, ver); err != nil {
-			log.Fatalf("Force version failed: %v", err)
+		if len(steps) == 0 {
+			fmt.Println("-- nothing to apply")
 		}
-		log.Printf("Forced version to %d", ver)
+		for _, step := range steps {
+			fmt.Printf("-- %s\n%s\n", step.File, step.SQL)
+		}
+		return
+	}
+
+	if err := apply(); err != nil {
+		logging.Fatal("migration failed", "error", err)
+	}
+	slog.Info("migrations applied")
+}
+
+func printStatus(m *database.Migrator) {
+	status, err := m.Status()
+	if err != nil {
+		logging.Fatal("status failed", "error", err)
+	}
+
+	switch {
+	case !status.Applied:
+		fmt.Println("Version: none")
+	case status.Dirty:
+		fmt.Printf("Version: %d (dirty, fix the schema and run force)\n", status.Version)
 	default:
-		log.Fatalf("Invalid action: %s", action)
+		fmt.Printf("Version: %d\n", status.Version)
+	}
+	if status.Unknown {
+		fmt.Println("Pending: unknown, the database is ahead of this binary's migrations")
+		return
+	}
+	if len(status.Pending) == 0 {
+		fmt.Println("Pending: none")
+		return
+	}
+	fmt.Println("Pending:")
+	for _, f := range status.Pending {
+		fmt.Printf("  %04d_%s\n", f.Version, f.Name)
+	}
+}
+
+func argument(args []string, name string) string {
+	if len(args) == 0 {
+		logging.Fatal("missing argument", "argument", name)
+	}
+	return args[0]
+}
+
+func atoi(s, name string) int {
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		logging.Fatal("invalid argument", "argument", name, "error", err)
 	}
+	return v
 }
@@ -1,27 +1,93 @@
 package main
 
 import (
-	"log"
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
 
+	"github.com/GFerreiroS/guild-manager/backend/internal/config"
 	"github.com/GFerreiroS/guild-manager/backend/internal/database"
+	"github.com/GFerreiroS/guild-manager/backend/internal/fixtures"
+	"github.com/GFerreiroS/guild-manager/backend/internal/logging"
 )
 
+const usage = `Usage: seed [flags] [fixture files or directories...]
+
+Upserts the fixtures (default: the fixtures directory) into the database.
+Seeding is idempotent and never deletes data unless -reset is given.
+
+Flags:
+`
+
 func main() {
-	db, err := database.NewPostgresDB()
+	reset := flag.Bool("reset", false, "drop and recreate the schema before seeding (refused in production)")
+	yes := flag.Bool("yes", false, "confirm -reset without prompting")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	paths := flag.Args()
+	if len(paths) == 0 {
+		paths = []string{"fixtures"}
+	}
+
+	cfg, err := config.Load()
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		logging.Fatal("failed to load config", "error", err)
+	}
+	if err := logging.Setup(cfg.Log.Level, cfg.Log.Format); err != nil {
+		logging.Fatal("failed to set up logging", "error", err)
 	}
 
-	// Run migrations first
-	if err := database.RunMigrations(db); err != nil {
-		log.Fatal("Migrations failed:", err)
+	// Load fixtures before connecting so a bad file fails fast.
+	set, err := fixtures.Load(paths...)
+	if err != nil {
+		logging.Fatal("failed to load fixtures", "error", err)
 	}
 
-	// Seed test data with error handling
-	log.Println("🌱 Seeding test data...")
-	if err := database.SeedTestData(db); err != nil {
-		log.Fatal("Seeding failed:", err)
+	db, err := database.NewPostgresDB(cfg.Database)
+	if err != nil {
+		logging.Fatal("failed to connect to database", "database", cfg.Database, "error", err)
 	}
 
-	log.Println("✅ Database seeded successfully")
+	if *reset {
+		if cfg.IsProduction() {
+			logging.Fatal("refusing to reset the database: the environment is production")
+		}
+		name := db.Migrator().CurrentDatabase()
+		if !*yes && !confirm(name) {
+			logging.Fatal("reset cancelled")
+		}
+		slog.Info("dropping and recreating the schema", "database", name)
+		if err := database.ResetSchema(db); err != nil {
+			logging.Fatal("reset failed", "error", err)
+		}
+	} else if err := database.RunMigrations(db); err != nil {
+		logging.Fatal("migrations failed", "error", err)
+	}
+
+	slog.Info("seeding fixtures")
+	summary, err := fixtures.Apply(context.Background(), db, set, time.Now())
+	if err != nil {
+		logging.Fatal("seeding failed", "error", err)
+	}
+
+	slog.Info("database seeded", "summary", summary.String())
+}
+
+// confirm asks the operator to type the database name.
+func confirm(name string) bool {
+	fmt.Printf("This deletes ALL data in database %q. Type its name to continue: ", name)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(answer) == name
 }
@@ -1,61 +1,76 @@
 package main
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"html/template"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
-	"strconv"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/gin-gonic/gin/render"
 
 	// Import your internal packages – adjust the import paths if necessary.
 	"github.com/GFerreiroS/guild-manager/backend/internal/api"
+	"github.com/GFerreiroS/guild-manager/backend/internal/auth"
 	"github.com/GFerreiroS/guild-manager/backend/internal/config"
 	"github.com/GFerreiroS/guild-manager/backend/internal/database"
+	"github.com/GFerreiroS/guild-manager/backend/internal/lifecycle"
+	"github.com/GFerreiroS/guild-manager/backend/internal/logging"
+	"github.com/GFerreiroS/guild-manager/backend/internal/metrics"
 	"github.com/GFerreiroS/guild-manager/backend/internal/middleware"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+	"github.com/GFerreiroS/guild-manager/backend/internal/roster"
+	"github.com/GFerreiroS/guild-manager/backend/internal/schedule"
+	"github.com/GFerreiroS/guild-manager/backend/internal/service"
+	"github.com/GFerreiroS/guild-manager/backend/internal/syncer"
+	"github.com/GFerreiroS/guild-manager/backend/internal/web"
+	"github.com/GFerreiroS/guild-manager/backend/pkg/blizzard"
 	"github.com/GFerreiroS/guild-manager/backend/pkg/redis"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-// createMyRenderer creates an HTML renderer for Gin.
-func createMyRenderer() render.HTMLRender {
-	tmpl := template.Must(template.New("status").Parse(`
-		<div class="space-y-2">
-			<p class="text-green-600">
-				Online Players: {{ .OnlinePlayers }}
-			</p>
-			<p class="text-gray-500 text-sm">
-				Last updated: {{ .LastUpdated }}
-			</p>
-		</div>
-	`))
-	return render.HTMLProduction{Template: tmpl}
-}
-
 // setupRouter configures the Gin router, registers routes, and applies middleware.
-func setupRouter(db *gorm.DB) *gin.Engine {
-	router := gin.Default()
-	router.HTMLRender = createMyRenderer()
-
-	// (Optional) You can add additional middleware here.
-	// For example, a custom middleware to store the underlying *sql.DB for health checks:
-	router.Use(func(c *gin.Context) {
-		if sqlDB, err := db.DB(); err == nil {
-			c.Set("db", sqlDB)
-		}
-		c.Next()
-	})
+// The guards, such as rate limiting and CSRF protection, apply to every route
+// registered here.
+func setupRouter(cfg *config.Config, db *sql.DB, deps api.Dependencies, guards ...gin.HandlerFunc) (*gin.Engine, error) {
+	router := gin.New()
+	router.SetHTMLTemplate(template.Must(web.Templates()))
+
+	// Gin trusts every proxy by default, which would let any client pick
+	// the IP its anonymous rate limit is counted against.
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	router.TrustedPlatform = config.TrustedPlatforms[cfg.Server.TrustedPlatform]
+
+	// Request IDs come first so the access log, recovered panics and every
+	// query of the request carry them.
+	router.Use(middleware.RequestID(), middleware.AccessLog(), gin.Recovery())
+
+	// Request counts and latencies, registered before the routes so every
+	// route is timed.
+	if cfg.Metrics.Enabled {
+		router.Use(metrics.Middleware())
+	}
+
+	// Registered before the routes, or they would not apply to them.
+	router.Use(guards...)
+
+	// Liveness and readiness probes, which check the connections directly.
+	api.RegisterHealthRoutes(router, db, deps.Redis)
 
 	// Register your API endpoints.
-	api.RegisterRoutes(router, db)
+	api.RegisterRoutes(router, deps)
 
-	return router
+	return router, nil
 }
 
 // connectDBWithRetries attempts to connect to PostgreSQL with retries.
@@ -63,81 +78,214 @@ func connectDBWithRetries(dsn string, maxRetries int, delay time.Duration) (*gor
 	var db *gorm.DB
 	var err error
 	for i := 0; i < maxRetries; i++ {
-		db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+		db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logging.NewGORMLogger()})
 		if err != nil {
-			log.Printf("Database connection attempt %d/%d failed: %v", i+1, maxRetries, err)
+			slog.Warn("database connection attempt failed", "attempt", i+1, "max_attempts", maxRetries, "error", err)
 			time.Sleep(delay)
 			continue
 		}
 		sqlDB, err := db.DB()
 		if err != nil {
-			log.Printf("Error retrieving underlying *sql.DB on attempt %d/%d: %v", i+1, maxRetries, err)
+			slog.Warn("failed to get the connection pool", "attempt", i+1, "max_attempts", maxRetries, "error", err)
 			time.Sleep(delay)
 			continue
 		}
 		if pingErr := sqlDB.Ping(); pingErr != nil {
-			log.Printf("Database ping attempt %d/%d failed: %v", i+1, maxRetries, pingErr)
+			slog.Warn("database ping failed", "attempt", i+1, "max_attempts", maxRetries, "error", pingErr)
 			time.Sleep(delay)
 			continue
 		}
-		log.Printf("Successfully connected to the database on attempt %d", i+1)
+		slog.Info("connected to the database", "attempt", i+1)
 		return db, nil
 	}
 	return nil, fmt.Errorf("could not connect to the database after %d attempts: %w", maxRetries, err)
 }
 
 func main() {
-	// Load configuration using Viper.
+	// Load and validate the configuration, failing on any invalid setting.
 	cfg, err := config.LoadConfig()
 	if err != nil {
-		log.Fatalf("Error loading configuration: %v", err)
-	}
-
-	// Build the DSN string from the configuration.
-	dsn := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Database.Host,
-		cfg.Database.Port,
-		cfg.Database.User,
-		cfg.Database.Password,
-		cfg.Database.DBName,
-		cfg.Database.SSLMode,
-	)
-
-	// Get maximum retry count from an environment variable or use a default.
-	maxRetries := 10
-	if s := os.Getenv("MIGRATION_MAX_RETRIES"); s != "" {
-		if v, err := strconv.Atoi(s); err == nil {
-			maxRetries = v
-		}
+		logging.Fatal("invalid configuration", "error", err)
+	}
+	if err := logging.Setup(cfg.Log.Level, cfg.Log.Format); err != nil {
+		logging.Fatal("failed to set up logging", "error", err)
+	}
+	if cfg.Log.Level != "debug" {
+		gin.SetMode(gin.ReleaseMode)
 	}
-	delay := 5 * time.Second
 
 	// Connect to the database with retry logic.
-	db, err := connectDBWithRetries(dsn, maxRetries, delay)
+	db, err := connectDBWithRetries(cfg.Database.DSN(), cfg.Database.ConnectRetries, 5*time.Second)
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		logging.Fatal("failed to connect to database", "database", cfg.Database, "error", err)
 	}
 
 	// (Optional) Run migrations. If you are managing your schema via SQL migrations,
 	// call the following function (ensure it’s exported from internal/database/migrations.go):
 	if err := database.RunMigrations(db); err != nil {
-		log.Fatal("Database migrations failed:", err)
+		logging.Fatal("database migrations failed", "error", err)
+	}
+
+	// The connection pool under GORM, for the readiness probe and metrics.
+	sqlDB, err := db.DB()
+	if err != nil {
+		logging.Fatal("failed to get the connection pool", "error", err)
 	}
 
+	// Prometheus instrumentation of queries and the connection pool.
+	if cfg.Metrics.Enabled {
+		if err := db.Use(metrics.GORMPlugin{}); err != nil {
+			logging.Fatal("failed to register query metrics", "error", err)
+		}
+		metrics.RegisterDBStats(sqlDB)
+	}
+
+	// Background workers and the resources to release on shutdown. Stop
+	// functions run in reverse order, so the pool closes last.
+	app := lifecycle.New()
+	app.OnStop("database pool", func(context.Context) error { return sqlDB.Close() })
+
 	// Initialize Redis client.
 	redisClient := redis.NewClient(cfg.Redis.Addr, cfg.Redis.Password, cfg.Redis.DB, cfg.Redis.Timeout)
+	app.OnStop("redis client", func(context.Context) error { return redisClient.Close() })
+	if cfg.Metrics.Enabled {
+		redisClient.Conn.AddHook(metrics.RedisHook{})
+	}
+
+	store := repository.NewPostgresStore(db)
+
+	// Battle.net login with sessions stored in Redis.
+	sessions := auth.NewSessionStore(redisClient.Conn, cfg.Session.Secret, cfg.Session.TTL, cfg.Session.Secure)
+	provider := auth.NewProvider(
+		cfg.BattleNet.ClientID,
+		cfg.BattleNet.ClientSecret,
+		cfg.BattleNet.RedirectURL,
+		cfg.BattleNet.Region,
+		cfg.BattleNet.OAuthURL,
+	)
+
+	// Character sync against the Blizzard profile API.
+	var characterSyncer *syncer.Syncer
+	var rosterImporter *roster.Importer
+	if cfg.BattleNet.ClientID != "" && cfg.BattleNet.ClientSecret != "" {
+		blizzardClient := blizzard.NewClient(blizzard.Config{
+			ClientID:     cfg.BattleNet.ClientID,
+			ClientSecret: cfg.BattleNet.ClientSecret,
+			Region:       cfg.BattleNet.Region,
+			OAuthBaseURL: cfg.BattleNet.OAuthURL,
+		})
+		rosterImporter = roster.NewImporter(store, blizzardClient)
+		characterSyncer = syncer.New(store, redisClient.Conn, blizzardClient, rosterImporter,
+			cfg.Sync.Interval, cfg.Sync.CheckInterval, cfg.Sync.BatchSize)
+		if cfg.Sync.Enabled {
+			app.Add("character sync", characterSyncer)
+		}
+	} else {
+		slog.Warn("Blizzard API credentials missing, character sync disabled")
+	}
+
+	// Recurring events from raid group schedules.
+	eventGenerator := schedule.NewGenerator(store, cfg.Schedule.WeeksAhead, cfg.Schedule.CheckInterval)
+	if cfg.Schedule.Enabled {
+		app.Add("event generator", eventGenerator)
+	}
+
+	services := service.New(store, service.Options{
+		Region:     cfg.BattleNet.Region,
+		RSVPCutoff: cfg.RSVP.Cutoff,
+		Generator:  eventGenerator,
+	})
+
+	// Probes and metrics are exempt from rate limiting and CSRF checks, so
+	// that scrapers and orchestrators are never throttled or handed cookies.
+	exempt := []string{"/livez", "/readyz", "/health", "/metrics"}
+
+	// Rate limits shared by every replica through Redis.
+	limiter := middleware.NewRateLimiter(redisClient.Conn, sessions, cfg.RateLimit.FailOpen)
+	policy := func(name string, limit int, window time.Duration) middleware.RatePolicy {
+		return middleware.RatePolicy{Name: name, Limit: limit, Window: window, Algorithm: cfg.RateLimit.Algorithm}
+	}
+	globalLimit := limiter.Limit(policy("global", cfg.RateLimit.RequestsPerMinute, time.Minute), exempt...)
+	csrf := middleware.CSRF(cfg.CSRF.Key, cfg.Session.Secure, exempt...)
 
 	// Create a new Gin router.
-	router := setupRouter(db)
+	router, err := setupRouter(cfg, sqlDB, api.Dependencies{
+		Redis:     redisClient.Conn,
+		Auth:      auth.NewHandler(store, provider, sessions),
+		Authz:     middleware.NewAuthorizer(store, sessions),
+		Services:  services,
+		Syncer:    characterSyncer,
+		Roster:    rosterImporter,
+		PublicURL: cfg.Server.PublicURL,
+		RateLimits: api.RateLimits{
+			Auth: limiter.Limit(policy("auth", cfg.RateLimit.AuthRequestsPerMinute, time.Minute)),
+			Sync: limiter.Limit(policy("sync", cfg.RateLimit.SyncRequestsPerHour, time.Hour)),
+		},
+	}, globalLimit, csrf)
+	if err != nil {
+		logging.Fatal("failed to set up router", "error", err)
+	}
+
+	// Metrics are served on the admin port when one is configured, so they
+	// need not be exposed publicly.
+	if cfg.Metrics.Enabled && cfg.Metrics.Addr != "" {
+		admin := http.NewServeMux()
+		admin.Handle("/metrics", metrics.Handler())
+		app.Add("metrics server", lifecycle.HTTPServer(&http.Server{
+			Addr:              cfg.Metrics.Addr,
+			Handler:           admin,
+			ReadHeaderTimeout: cfg.Server.ReadTimeout,
+		}, cfg.Server.ShutdownTimeout))
+		slog.Info("metrics served on admin listener", "addr", cfg.Metrics.Addr)
+	} else if cfg.Metrics.Enabled {
+		router.GET("/metrics", gin.WrapH(metrics.Handler()))
+	}
+
+	srv := &http.Server{
+		Addr:              cfg.Server.Addr,
+		Handler:           router,
+		ReadHeaderTimeout: cfg.Server.ReadTimeout,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+	}
 
-	// Apply rate-limiting middleware using Redis.
-	router.Use(middleware.RateLimitMiddleware(redisClient.Conn, cfg.RateLimit.RequestsPerMinute))
+	// SIGINT or SIGTERM (docker stop) starts a graceful shutdown.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	app.Start(ctx)
 
 	// Start the HTTP server.
-	addr := ":8080"
-	log.Printf("🚀 Server starting on %s", addr)
-	if err := http.ListenAndServe(addr, router); err != nil {
-		log.Fatalf("Server failed: %v", err)
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("server starting", "addr", cfg.Server.Addr, "environment", cfg.Environment)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- err
+		}
+	}()
+
+	exitCode := 0
+	select {
+	case <-ctx.Done():
+		slog.Info("shutting down, draining in-flight requests")
+	case err := <-serveErr:
+		slog.Error("server failed", "error", err)
+		exitCode = 1
+	}
+	stop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("HTTP server shutdown failed", "error", err)
+		exitCode = 1
+	}
+	if err := app.Stop(shutdownCtx); err != nil {
+		slog.Error("shutdown failed", "error", err)
+		exitCode = 1
+	}
+	slog.Info("server stopped")
+	if exitCode != 0 {
+		os.Exit(exitCode)
 	}
 }
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	goredis "github.com/go-redis/redis/v8"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/auth"
+	"github.com/GFerreiroS/guild-manager/backend/internal/middleware"
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+	"github.com/GFerreiroS/guild-manager/backend/internal/service"
+	"github.com/GFerreiroS/guild-manager/backend/internal/web"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// testServer serves the routes from an in-memory store, with sessions kept
+// in an in-process Redis.
+type testServer struct {
+	router   *gin.Engine
+	store    repository.Store
+	svc      *service.Services
+	sessions *auth.SessionStore
+}
+
+// testOption changes the dependencies of a testServer before its routes are
+// registered.
+type testOption func(t *testing.T, store repository.Store, deps *Dependencies)
+
+func newTestServer(t *testing.T, opts ...testOption) *testServer {
+	t.Helper()
+
+	rdb := goredis.NewClient(&goredis.Options{Addr: miniredis.RunT(t).Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	store := repository.NewMemoryStore()
+	sessions := auth.NewSessionStore(rdb, "test-secret", time.Hour, false)
+	svc := service.New(store, service.Options{Region: "eu", RSVPCutoff: time.Hour})
+
+	deps := Dependencies{
+		Redis:     rdb,
+		Auth:      auth.NewHandler(store, nil, sessions),
+		Authz:     middleware.NewAuthorizer(store, sessions),
+		Services:  svc,
+		PublicURL: "https://guild.example/",
+	}
+	for _, opt := range opts {
+		opt(t, store, &deps)
+	}
+
+	router := gin.New()
+	router.SetHTMLTemplate(template.Must(web.Templates()))
+	RegisterRoutes(router, deps)
+	return &testServer{router: router, store: store, svc: svc, sessions: sessions}
+}
+
+// user creates a user with role and returns it with a session cookie. The
+// session holds the blizzard.CharacterKey of the account's characters.
+func (s *testServer) user(t *testing.T, name, role string, characters ...string) (*models.User, *http.Cookie) {
+	t.Helper()
+
+	ctx := context.Background()
+	user := &models.User{BattleNetID: name, Username: name, Role: role}
+	if err := s.store.Users().Create(ctx, user); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	_, value, err := s.sessions.Create(ctx, user.ID, user.BattleNetID, characters)
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	return user, &http.Cookie{Name: auth.SessionCookie, Value: value}
+}
+
+// guild creates a guild led by master.
+func (s *testServer) guild(t *testing.T, name string, master *models.User) *models.Guild {
+	t.Helper()
+
+	guild, err := s.svc.Guilds.Create(context.Background(), master.ID, service.GuildInput{Name: name, Realm: "Silvermoon", Faction: "horde"})
+	if err != nil {
+		t.Fatalf("create guild: %v", err)
+	}
+	return guild
+}
+
+// do sends a request as the owner of cookie, encoding body as JSON when it
+// is not nil.
+func (s *testServer) do(t *testing.T, method, path string, cookie *http.Cookie, body any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatalf("encode body: %v", err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	return s.serve(req)
+}
+
+// serve sends req as is.
+func (s *testServer) serve(req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+// decode unmarshals the JSON body of w into v.
+func decode(t *testing.T, w *httptest.ResponseRecorder, v any) {
+	t.Helper()
+
+	if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+		t.Fatalf("decode %q: %v", w.Body.String(), err)
+	}
+}
+
+func wantStatus(t *testing.T, w *httptest.ResponseRecorder, status int) {
+	t.Helper()
+
+	if w.Code != status {
+		t.Fatalf("status = %d, want %d; body: %s", w.Code, status, w.Body.String())
+	}
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/auth"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+	"github.com/GFerreiroS/guild-manager/backend/internal/service"
+)
+
+// defaultReportRange is the period reports cover without ?from=.
+const defaultReportRange = 90 * 24 * time.Hour
+
+// attendanceRequest records the attendance of several characters at once.
+type attendanceRequest struct {
+	Records []attendanceRecord `json:"records" binding:"required,min=1,dive"`
+}
+
+type attendanceRecord struct {
+	CharacterID string `json:"character_id" binding:"required,uuid"`
+	Status      string `json:"status" binding:"required,attendance"`
+	Note        string `json:"note" binding:"max=1000"`
+}
+
+func (h *resourceHandler) listAttendance(c *gin.Context) {
+	event, ok := h.loadEvent(c)
+	if !ok {
+		return
+	}
+
+	records, err := h.svc.Attendance.List(c.Request.Context(), event)
+	if err != nil {
+		writeDBError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": records})
+}
+
+// putAttendance creates or replaces attendance records for an event that has
+// already started.
+func (h *resourceHandler) putAttendance(c *gin.Context) {
+	var req attendanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+	event, ok := h.loadEvent(c)
+	if !ok {
+		return
+	}
+
+	in := make([]service.AttendanceInput, len(req.Records))
+	for i, r := range req.Records {
+		in[i] = service.AttendanceInput{CharacterID: r.CharacterID, Status: r.Status, Note: r.Note}
+	}
+	records, err := h.svc.Attendance.Record(c.Request.Context(), event, auth.CurrentSession(c).UserID, in)
+	switch {
+	case errors.Is(err, service.ErrEventNotStarted):
+		writeError(c, http.StatusBadRequest, codeInvalidInput, err.Error())
+		return
+	case errors.Is(err, service.ErrForeignCharacter):
+		writeError(c, http.StatusBadRequest, codeInvalidInput, "records must reference distinct characters of this guild")
+		return
+	case err != nil:
+		writeDBError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": records})
+}
+
+func (h *resourceHandler) deleteAttendance(c *gin.Context) {
+	event, ok := h.loadEvent(c)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.Attendance.Delete(c.Request.Context(), event, c.Param("characterID")); err != nil {
+		writeDBError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// guildAttendanceReport reports attendance per character over
+// ?from=/?to=, optionally limited to ?raid_group_id=.
+func (h *resourceHandler) guildAttendanceReport(c *gin.Context) {
+	filter, ok := reportFilter(c)
+	if !ok {
+		return
+	}
+	filter.RaidGroupID = c.Query("raid_group_id")
+	h.writeAttendanceReport(c, filter)
+}
+
+// raidGroupAttendanceReport reports the attendance of a raid group's events.
+func (h *resourceHandler) raidGroupAttendanceReport(c *gin.Context) {
+	group, ok := h.loadRaidGroup(c, false)
+	if !ok {
+		return
+	}
+	filter, ok := reportFilter(c)
+	if !ok {
+		return
+	}
+	filter.RaidGroupID = group.ID
+	h.writeAttendanceReport(c, filter)
+}
+
+func (h *resourceHandler) writeAttendanceReport(c *gin.Context, filter repository.AttendanceFilter) {
+	report, err := h.svc.Attendance.Report(c.Request.Context(), filter)
+	if err != nil {
+		writeDBError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// reportFilter parses ?from= and ?to= (RFC 3339), defaulting to the last 90
+// days.
+func reportFilter(c *gin.Context) (repository.AttendanceFilter, bool) {
+	filter := repository.AttendanceFilter{GuildID: currentGuild(c).ID, To: time.Now().UTC()}
+	for param, dst := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		s := c.Query(param)
+		if s == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			writeError(c, http.StatusBadRequest, codeInvalidInput, param+" must be an RFC 3339 timestamp")
+			return filter, false
+		}
+		*dst = t
+	}
+	if filter.From.IsZero() {
+		filter.From = filter.To.Add(-defaultReportRange)
+	}
+	if !filter.From.Before(filter.To) {
+		writeError(c, http.StatusBadRequest, codeInvalidInput, "from must be before to")
+		return filter, false
+	}
+	return filter, true
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/service"
+)
+
+func TestAttendance(t *testing.T) {
+	s := newTestServer(t)
+	master, cookie := s.user(t, "master", models.RoleMember)
+	other, _ := s.user(t, "other", models.RoleMember)
+	guild := s.guild(t, "Guild", master)
+	otherGuild := s.guild(t, "Other", other)
+
+	ctx := context.Background()
+	character, err := s.svc.Characters.Create(ctx, guild, service.CharacterInput{Name: "Thrall", Realm: "Silvermoon", Class: "shaman"})
+	if err != nil {
+		t.Fatalf("create character: %v", err)
+	}
+	foreign, err := s.svc.Characters.Create(ctx, otherGuild, service.CharacterInput{Name: "Jaina", Realm: "Silvermoon", Class: "mage"})
+	if err != nil {
+		t.Fatalf("create character: %v", err)
+	}
+	newEvent := func(at time.Time) string {
+		event, err := s.svc.Events.Create(ctx, guild.ID, master.ID, service.EventInput{RaidName: "Nerub-ar Palace", Difficulty: "heroic", ScheduledAt: at})
+		if err != nil {
+			t.Fatalf("create event: %v", err)
+		}
+		return "/api/v1/guilds/" + guild.ID + "/events/" + event.ID + "/attendance"
+	}
+	past, future := newEvent(time.Now().Add(-time.Hour)), newEvent(time.Now().Add(24*time.Hour))
+	record := func(characterID, status string) map[string]any {
+		return map[string]any{"records": []map[string]any{{"character_id": characterID, "status": status}}}
+	}
+
+	wantStatus(t, s.do(t, http.MethodPut, future, cookie, record(character.ID, models.AttendancePresent)), http.StatusBadRequest)
+	wantStatus(t, s.do(t, http.MethodPut, past, cookie, record(foreign.ID, models.AttendancePresent)), http.StatusBadRequest)
+	wantStatus(t, s.do(t, http.MethodPut, past, cookie, record(character.ID, "asleep")), http.StatusBadRequest)
+
+	wantStatus(t, s.do(t, http.MethodPut, past, cookie, record(character.ID, models.AttendancePresent)), http.StatusOK)
+	// Recording again replaces the record.
+	wantStatus(t, s.do(t, http.MethodPut, past, cookie, record(character.ID, models.AttendanceLate)), http.StatusOK)
+
+	w := s.do(t, http.MethodGet, past, cookie, nil)
+	wantStatus(t, w, http.StatusOK)
+	var resp struct {
+		Data []models.Attendance `json:"data"`
+	}
+	decode(t, w, &resp)
+	if len(resp.Data) != 1 || resp.Data[0].Status != models.AttendanceLate || resp.Data[0].RecordedBy == nil || *resp.Data[0].RecordedBy != master.ID {
+		t.Fatalf("attendance = %+v, want one late record by %s", resp.Data, master.ID)
+	}
+
+	wantStatus(t, s.do(t, http.MethodDelete, past+"/"+character.ID, cookie, nil), http.StatusNoContent)
+	wantStatus(t, s.do(t, http.MethodDelete, past+"/"+character.ID, cookie, nil), http.StatusNotFound)
+}
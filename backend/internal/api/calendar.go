@@ -0,0 +1,233 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/auth"
+	"github.com/GFerreiroS/guild-manager/backend/internal/calendar"
+	"github.com/GFerreiroS/guild-manager/backend/internal/middleware"
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+	"github.com/GFerreiroS/guild-manager/backend/internal/service"
+)
+
+const (
+	// feedHistory is how far back feeds include past events.
+	feedHistory = 30 * 24 * time.Hour
+	// defaultEventLength is used for events without an end time.
+	defaultEventLength = 3 * time.Hour
+)
+
+// calendarHandler serves the ICS feeds and their token management.
+type calendarHandler struct {
+	svc   *service.Services
+	authz *middleware.Authorizer
+	// baseURL is the public scheme and host feed URLs start with.
+	baseURL string
+}
+
+// feedGuild is a guild feed listed by GET /api/v1/me/calendar.
+type feedGuild struct {
+	GuildID string `json:"guild_id"`
+	Name    string `json:"name"`
+	URL     string `json:"url"`
+}
+
+// registerCalendarRoutes registers the feeds, which are authenticated by the
+// token in their URL because calendar clients cannot send session cookies.
+func registerCalendarRoutes(router *gin.Engine, deps Dependencies) {
+	h := &calendarHandler{svc: deps.Services, authz: deps.Authz, baseURL: strings.TrimRight(deps.PublicURL, "/")}
+
+	me := router.Group("/api/v1/me/calendar", deps.Auth.Sessions().RequireSession())
+	me.GET("", h.getFeeds)
+	me.POST("/rotate", h.rotateFeedToken)
+
+	router.GET("/api/calendar/:token/events.ics", h.personalFeed)
+	router.GET("/api/calendar/:token/guilds/:id/events.ics", h.guildFeed)
+}
+
+// getFeeds returns the caller's feed URLs, creating their token on first use.
+func (h *calendarHandler) getFeeds(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, err := h.svc.Users.Get(ctx, auth.CurrentSession(c).UserID)
+	if err != nil {
+		writeDBError(c, err)
+		return
+	}
+	if err := h.svc.Users.EnsureFeedToken(ctx, user); err != nil {
+		writeDBError(c, err)
+		return
+	}
+	h.writeFeeds(c, user)
+}
+
+// rotateFeedToken replaces the caller's token, invalidating every feed URL
+// handed out before.
+func (h *calendarHandler) rotateFeedToken(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, err := h.svc.Users.Get(ctx, auth.CurrentSession(c).UserID)
+	if err != nil {
+		writeDBError(c, err)
+		return
+	}
+	if err := h.svc.Users.RotateFeedToken(ctx, user); err != nil {
+		writeDBError(c, err)
+		return
+	}
+	h.writeFeeds(c, user)
+}
+
+// personalFeed lists the events the user's characters were asked to attend.
+func (h *calendarHandler) personalFeed(c *gin.Context) {
+	user, ok := h.feedUser(c)
+	if !ok {
+		return
+	}
+
+	events, err := h.svc.Events.List(c.Request.Context(), repository.EventFilter{
+		UserID: user.ID,
+		From:   time.Now().Add(-feedHistory),
+	})
+	if err != nil {
+		writeDBError(c, err)
+		return
+	}
+	h.writeCalendar(c, user, user.Username+" raids", events)
+}
+
+// guildFeed lists all events of a guild the user may view.
+func (h *calendarHandler) guildFeed(c *gin.Context) {
+	user, ok := h.feedUser(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	guild, err := h.svc.Guilds.Get(ctx, c.Param("id"))
+	if err != nil {
+		writeDBError(c, err)
+		return
+	}
+	allowed, err := h.authz.Can(ctx, user.ID, guild.ID, middleware.PermViewGuild)
+	if err != nil {
+		writeDBError(c, err)
+		return
+	}
+	if !allowed {
+		writeError(c, http.StatusForbidden, codeForbidden, "not a member of this guild")
+		return
+	}
+
+	events, err := h.svc.Events.List(ctx, repository.EventFilter{
+		GuildID: guild.ID,
+		From:    time.Now().Add(-feedHistory),
+	})
+	if err != nil {
+		writeDBError(c, err)
+		return
+	}
+	h.writeCalendar(c, user, guild.Name+" raids", events)
+}
+
+// feedUser resolves the :token parameter, answering 404 for unknown tokens.
+func (h *calendarHandler) feedUser(c *gin.Context) (*models.User, bool) {
+	user, err := h.svc.Users.ByFeedToken(c.Request.Context(), c.Param("token"))
+	if errors.Is(err, repository.ErrNotFound) {
+		writeError(c, http.StatusNotFound, codeNotFound, "calendar feed not found")
+		return nil, false
+	}
+	if err != nil {
+		writeDBError(c, err)
+		return nil, false
+	}
+	return user, true
+}
+
+// writeFeeds answers with the personal feed URL and one URL per guild.
+func (h *calendarHandler) writeFeeds(c *gin.Context, user *models.User) {
+	guilds, err := h.svc.Guilds.ListForUser(c.Request.Context(), user.ID)
+	if err != nil {
+		writeDBError(c, err)
+		return
+	}
+
+	base := h.baseURL + "/api/calendar/" + *user.FeedToken
+	feeds := make([]feedGuild, 0, len(guilds))
+	for _, g := range guilds {
+		feeds = append(feeds, feedGuild{GuildID: g.ID, Name: g.Name, URL: base + "/guilds/" + g.ID + "/events.ics"})
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"personal_url": base + "/events.ics",
+		"guilds":       feeds,
+	})
+}
+
+// writeCalendar renders events as ICS, describing the user's RSVPs.
+func (h *calendarHandler) writeCalendar(c *gin.Context, user *models.User, name string, events []models.Event) {
+	rsvps, err := h.userRSVPs(c.Request.Context(), user.ID, events)
+	if err != nil {
+		writeDBError(c, err)
+		return
+	}
+
+	cal := calendar.Calendar{Name: name}
+	for _, e := range events {
+		end := e.ScheduledAt.Add(defaultEventLength)
+		if e.EndsAt != nil {
+			end = *e.EndsAt
+		}
+		description := "Your RSVP: none"
+		if answers := rsvps[e.ID]; len(answers) > 0 {
+			description = "Your RSVP: " + strings.Join(answers, ", ")
+		}
+		cal.Events = append(cal.Events, calendar.Event{
+			ID:          e.ID,
+			Summary:     fmt.Sprintf("%s (%s)", e.RaidName, capitalize(e.Difficulty)),
+			Description: description,
+			Start:       e.ScheduledAt,
+			End:         end,
+			Modified:    e.UpdatedAt,
+			Sequence:    e.Sequence,
+		})
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", calendar.Render(cal, time.Now()))
+}
+
+// userRSVPs returns "Character: status" entries of the user's characters,
+// keyed by event ID.
+func (h *calendarHandler) userRSVPs(ctx context.Context, userID string, events []models.Event) (map[string][]string, error) {
+	if len(events) == 0 {
+		return nil, nil
+	}
+	ids := make([]string, len(events))
+	for i, e := range events {
+		ids[i] = e.ID
+	}
+
+	rows, err := h.svc.Users.RSVPs(ctx, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	rsvps := make(map[string][]string, len(rows))
+	for _, r := range rows {
+		rsvps[r.EventID] = append(rsvps[r.EventID], r.CharacterName+": "+r.Status)
+	}
+	return rsvps, nil
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+)
+
+// monthLayout is the format of the ?month= parameter.
+const monthLayout = "2006-01"
+
+// calendarDay is a cell of the month grid.
+type calendarDay struct {
+	Date    time.Time
+	InMonth bool
+	Today   bool
+	Events  []models.Event
+}
+
+// calendar shows the guild's raids of ?month= (YYYY-MM, default the current
+// month) as a grid of weeks starting on Monday. Times are shown in UTC. HTMX
+// requests get only the grid.
+func (h *pageHandler) calendar(c *gin.Context) {
+	guild := pageGuild(c)
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if m := c.Query("month"); m != "" {
+		parsed, err := time.Parse(monthLayout, m)
+		if err != nil {
+			h.renderError(c, http.StatusBadRequest, "month must look like 2024-09.")
+			return
+		}
+		month = parsed
+	}
+
+	// Pad the grid to whole weeks, Monday to Sunday.
+	start := month.AddDate(0, 0, -((int(month.Weekday()) + 6) % 7))
+	end := month.AddDate(0, 1, 0)
+	end = end.AddDate(0, 0, (7-(int(end.Weekday())+6)%7)%7)
+
+	events, err := h.svc.Events.List(c.Request.Context(), repository.EventFilter{
+		GuildID: guild.ID,
+		From:    start,
+		To:      end,
+	})
+	if err != nil {
+		h.renderError(c, http.StatusInternalServerError, "The calendar could not be loaded.")
+		return
+	}
+
+	var weeks [][]calendarDay
+	next := 0
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		if (int(day.Weekday())+6)%7 == 0 {
+			weeks = append(weeks, nil)
+		}
+		cell := calendarDay{Date: day, InMonth: day.Month() == month.Month(), Today: day.Equal(today)}
+		for next < len(events) && events[next].ScheduledAt.UTC().Before(day.AddDate(0, 0, 1)) {
+			cell.Events = append(cell.Events, events[next])
+			next++
+		}
+		weeks[len(weeks)-1] = append(weeks[len(weeks)-1], cell)
+	}
+
+	tmpl := "pages/calendar.html"
+	if isHTMX(c) {
+		tmpl = "partials/calendar_month.html"
+	}
+	h.render(c, http.StatusOK, tmpl, gin.H{
+		"Title":    guild.Name + " calendar",
+		"Month":    month,
+		"Previous": month.AddDate(0, -1, 0).Format(monthLayout),
+		"Next":     month.AddDate(0, 1, 0).Format(monthLayout),
+		"Weekdays": []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"},
+		"Weeks":    weeks,
+	})
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+)
+
+func TestCalendarFeedURLs(t *testing.T) {
+	s := newTestServer(t)
+	user, cookie := s.user(t, "user", models.RoleMember)
+	guild := s.guild(t, "Guild", user)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/me/calendar", nil)
+	req.AddCookie(cookie)
+	req.Host = "attacker.example"
+	req.Header.Set("X-Forwarded-Proto", "gopher")
+	req.Header.Set("X-Forwarded-Host", "attacker.example")
+	w := s.serve(req)
+	wantStatus(t, w, http.StatusOK)
+
+	var resp struct {
+		PersonalURL string `json:"personal_url"`
+		Guilds      []struct {
+			GuildID string `json:"guild_id"`
+			URL     string `json:"url"`
+		} `json:"guilds"`
+	}
+	decode(t, w, &resp)
+	if !strings.HasPrefix(resp.PersonalURL, "https://guild.example/api/calendar/") || !strings.HasSuffix(resp.PersonalURL, "/events.ics") {
+		t.Fatalf("personal_url = %q, want it under PublicURL", resp.PersonalURL)
+	}
+	if len(resp.Guilds) != 1 || resp.Guilds[0].URL != strings.TrimSuffix(resp.PersonalURL, "/events.ics")+"/guilds/"+guild.ID+"/events.ics" {
+		t.Fatalf("guilds = %+v, want the feed of %s", resp.Guilds, guild.ID)
+	}
+
+	// The feed is served by its token alone.
+	feed := s.do(t, http.MethodGet, strings.TrimPrefix(resp.PersonalURL, "https://guild.example"), nil, nil)
+	wantStatus(t, feed, http.StatusOK)
+	if !strings.HasPrefix(feed.Body.String(), "BEGIN:VCALENDAR") {
+		t.Fatalf("feed = %q, want an iCalendar", feed.Body.String())
+	}
+}
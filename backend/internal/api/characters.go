@@ -0,0 +1,124 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+	"github.com/GFerreiroS/guild-manager/backend/internal/service"
+)
+
+// characterRequest is the body for creating or replacing a character.
+type characterRequest struct {
+	Name          string  `json:"name" binding:"required,max=255"`
+	Realm         string  `json:"realm" binding:"required,max=255"`
+	Class         string  `json:"class" binding:"required,wowclass"`
+	Spec          string  `json:"spec" binding:"max=50"`
+	PreferredRole *string `json:"preferred_role" binding:"omitempty,raidrole"`
+	OffSpec       string  `json:"off_spec" binding:"max=50"`
+	Ilvl          int     `json:"ilvl" binding:"min=0"`
+	Level         int     `json:"level" binding:"min=0"`
+	UserID        *string `json:"user_id" binding:"omitempty,uuid"`
+	RaidGroupID   *string `json:"raid_group_id" binding:"omitempty,uuid"`
+}
+
+func (r characterRequest) input() service.CharacterInput {
+	return service.CharacterInput{
+		Name:          r.Name,
+		Realm:         r.Realm,
+		Class:         r.Class,
+		Spec:          r.Spec,
+		PreferredRole: r.PreferredRole,
+		OffSpec:       r.OffSpec,
+		Ilvl:          r.Ilvl,
+		Level:         r.Level,
+		UserID:        r.UserID,
+		RaidGroupID:   r.RaidGroupID,
+	}
+}
+
+// listCharacters lists the guild's characters. Characters that left the
+// in-game guild are only included with ?include_left=true.
+func (h *resourceHandler) listCharacters(c *gin.Context) {
+	page, ok := paginate(c)
+	if !ok {
+		return
+	}
+
+	characters, err := h.svc.Characters.List(c.Request.Context(), repository.CharacterFilter{
+		GuildID:     currentGuild(c).ID,
+		Class:       c.Query("class"),
+		IncludeLeft: c.Query("include_left") == "true",
+		Page:        page,
+	})
+	if err != nil {
+		writeDBError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": characters})
+}
+
+func (h *resourceHandler) createCharacter(c *gin.Context) {
+	var req characterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	character, err := h.svc.Characters.Create(c.Request.Context(), currentGuild(c), req.input())
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, character)
+}
+
+func (h *resourceHandler) getCharacter(c *gin.Context) {
+	character, ok := h.loadCharacter(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, character)
+}
+
+func (h *resourceHandler) updateCharacter(c *gin.Context) {
+	var req characterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+	character, ok := h.loadCharacter(c)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.Characters.Update(c.Request.Context(), currentGuild(c), character, req.input()); err != nil {
+		writeServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, character)
+}
+
+func (h *resourceHandler) deleteCharacter(c *gin.Context) {
+	character, ok := h.loadCharacter(c)
+	if !ok {
+		return
+	}
+	if err := h.svc.Characters.Delete(c.Request.Context(), character); err != nil {
+		writeDBError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// loadCharacter loads :characterID within the current guild.
+func (h *resourceHandler) loadCharacter(c *gin.Context) (*models.Character, bool) {
+	character, err := h.svc.Characters.Get(c.Request.Context(), currentGuild(c).ID, c.Param("characterID"))
+	if err != nil {
+		writeDBError(c, err)
+		return nil, false
+	}
+	return character, true
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/service"
+)
+
+func TestCreateCharacterReferences(t *testing.T) {
+	s := newTestServer(t)
+	master, cookie := s.user(t, "master", models.RoleMember)
+	other, _ := s.user(t, "other", models.RoleMember)
+	guild := s.guild(t, "Guild", master)
+	otherGuild := s.guild(t, "Other", other)
+
+	ctx := context.Background()
+	group, err := s.svc.RaidGroups.Create(ctx, guild.ID, service.RaidGroupInput{Name: "Main"})
+	if err != nil {
+		t.Fatalf("create raid group: %v", err)
+	}
+	otherGroup, err := s.svc.RaidGroups.Create(ctx, otherGuild.ID, service.RaidGroupInput{Name: "Main"})
+	if err != nil {
+		t.Fatalf("create raid group: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		body map[string]any
+		want int
+	}{
+		{"own raid group and member", map[string]any{"raid_group_id": group.ID, "user_id": master.ID}, http.StatusCreated},
+		{"raid group of another guild", map[string]any{"raid_group_id": otherGroup.ID}, http.StatusBadRequest},
+		{"user outside the guild", map[string]any{"user_id": other.ID}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := map[string]any{"name": "Thrall", "realm": "Silvermoon", "class": "shaman"}
+			for k, v := range tt.body {
+				body[k] = v
+			}
+			w := s.do(t, http.MethodPost, "/api/v1/guilds/"+guild.ID+"/characters", cookie, body)
+			wantStatus(t, w, tt.want)
+		})
+	}
+}
+
+func TestCreateCharacterNameIgnoresCase(t *testing.T) {
+	s := newTestServer(t)
+	master, cookie := s.user(t, "master", models.RoleMember)
+	guild := s.guild(t, "Guild", master)
+
+	path := "/api/v1/guilds/" + guild.ID + "/characters"
+	wantStatus(t, s.do(t, http.MethodPost, path, cookie, map[string]any{"name": "Thrall", "realm": "Silvermoon", "class": "shaman"}), http.StatusCreated)
+	wantStatus(t, s.do(t, http.MethodPost, path, cookie, map[string]any{"name": "THRALL", "realm": "Silvermoon", "class": "shaman"}), http.StatusConflict)
+}
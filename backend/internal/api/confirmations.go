@@ -0,0 +1,163 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/middleware"
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+)
+
+// confirmationRequest is the body for answering on behalf of a character.
+type confirmationRequest struct {
+	CharacterID string `json:"character_id" binding:"required,uuid"`
+	Status      string `json:"status" binding:"required,rsvp"`
+	Reason      string `json:"reason" binding:"max=1000"`
+}
+
+// responseRequest is the body for answering with a known confirmation or
+// character.
+type responseRequest struct {
+	Status string `json:"status" binding:"required,rsvp"`
+	Reason string `json:"reason" binding:"max=1000"`
+}
+
+// listConfirmations lists an event's confirmations, optionally filtered by
+// ?status=.
+func (h *resourceHandler) listConfirmations(c *gin.Context) {
+	event, ok := h.loadEvent(c)
+	if !ok {
+		return
+	}
+
+	confirmations, err := h.svc.Confirmations.List(c.Request.Context(), event.ID, c.Query("status"))
+	if err != nil {
+		writeDBError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": confirmations})
+}
+
+// createConfirmation records a character's answer to an event, updating the
+// pending confirmation if there is one.
+func (h *resourceHandler) createConfirmation(c *gin.Context) {
+	var req confirmationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+	h.respond(c, req.CharacterID, responseRequest{Status: req.Status, Reason: req.Reason})
+}
+
+// respondForCharacter is PUT /events/:eventID/rsvp/:characterID.
+func (h *resourceHandler) respondForCharacter(c *gin.Context) {
+	var req responseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+	h.respond(c, c.Param("characterID"), req)
+}
+
+func (h *resourceHandler) getConfirmation(c *gin.Context) {
+	confirmation, ok := h.loadConfirmation(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, confirmation)
+}
+
+func (h *resourceHandler) updateConfirmation(c *gin.Context) {
+	var req responseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+	confirmation, ok := h.loadConfirmation(c)
+	if !ok {
+		return
+	}
+	h.respond(c, confirmation.CharacterID, req)
+}
+
+func (h *resourceHandler) deleteConfirmation(c *gin.Context) {
+	confirmation, ok := h.loadConfirmation(c)
+	if !ok {
+		return
+	}
+	if err := h.svc.Confirmations.Delete(c.Request.Context(), confirmation); err != nil {
+		writeDBError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// respond creates or updates the confirmation of a character of the event's
+// guild. The response is 201 when a confirmation had to be created.
+func (h *resourceHandler) respond(c *gin.Context, characterID string, req responseRequest) {
+	event, ok := h.loadEvent(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	character, err := h.svc.Characters.Get(ctx, event.GuildID, characterID)
+	if errors.Is(err, repository.ErrNotFound) {
+		writeError(c, http.StatusBadRequest, codeInvalidInput, "character_id must reference a character of this guild")
+		return
+	}
+	if err != nil {
+		writeDBError(c, err)
+		return
+	}
+	if !canAnswerFor(c, character) {
+		return
+	}
+
+	confirmation, created, err := h.svc.Confirmations.Respond(ctx, event, character, req.Status, req.Reason)
+	if err != nil {
+		writeDBError(c, err)
+		return
+	}
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, confirmation)
+}
+
+// canAnswerFor allows officers to answer for any character and members only
+// for characters they own, answering 403 otherwise.
+func canAnswerFor(c *gin.Context, character *models.Character) bool {
+	if middleware.HasPermission(c, middleware.PermManageEvents) {
+		return true
+	}
+	user := middleware.CurrentUser(c)
+	if user != nil && character.UserID != nil && *character.UserID == user.ID {
+		return true
+	}
+	c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+		"error":  "you can only answer for your own characters",
+		"code":   codeForbidden,
+		"reason": middleware.ReasonNotCharacterOwner,
+	})
+	return false
+}
+
+// loadConfirmation loads :confirmationID within the event in the path.
+func (h *resourceHandler) loadConfirmation(c *gin.Context) (*models.Confirmation, bool) {
+	event, ok := h.loadEvent(c)
+	if !ok {
+		return nil, false
+	}
+
+	confirmation, err := h.svc.Confirmations.Get(c.Request.Context(), event.ID, c.Param("confirmationID"))
+	if err != nil {
+		writeDBError(c, err)
+		return nil, false
+	}
+	return confirmation, true
+}
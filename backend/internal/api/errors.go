@@ -0,0 +1,129 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+	"github.com/GFerreiroS/guild-manager/backend/internal/service"
+)
+
+// Error codes returned in the "code" field of error bodies.
+const (
+	codeInvalidInput = "invalid_input"
+	codeUnauthorized = "unauthorized"
+	codeForbidden    = "forbidden"
+	codeNotFound     = "not_found"
+	codeConflict     = "conflict"
+	codeUnavailable  = "unavailable"
+	codeInternal     = "internal_error"
+)
+
+// Postgres error codes we map to client errors.
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgCheckViolation      = "23514"
+	pgNotNullViolation    = "23502"
+	pgInvalidTextRepr     = "22P02"
+)
+
+// writeError sends the JSON error body used by every API endpoint.
+func writeError(c *gin.Context, status int, code, message string) {
+	c.AbortWithStatusJSON(status, gin.H{"error": message, "code": code})
+}
+
+// writeBindError reports a request body that failed to bind or validate,
+// listing the offending fields when available.
+func writeBindError(c *gin.Context, err error) {
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		writeError(c, http.StatusBadRequest, codeInvalidInput, "invalid request body: "+err.Error())
+		return
+	}
+
+	fields := make(map[string]string, len(verrs))
+	for _, fe := range verrs {
+		fields[fe.Field()] = validationMessage(fe)
+	}
+	c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+		"error":  "validation failed",
+		"code":   codeInvalidInput,
+		"fields": fields,
+	})
+}
+
+// writeServiceError maps the business rule errors of the service layer to
+// HTTP responses and leaves anything else to writeDBError.
+func writeServiceError(c *gin.Context, err error) {
+	var scheduleErr *models.ScheduleError
+	switch {
+	case errors.Is(err, service.ErrLastGuildMaster):
+		writeError(c, http.StatusConflict, codeConflict, err.Error())
+	case errors.Is(err, service.ErrForeignCharacter):
+		writeError(c, http.StatusBadRequest, codeInvalidInput, "character_ids must reference characters of this guild")
+	case errors.Is(err, service.ErrForeignRaidGroup):
+		writeError(c, http.StatusBadRequest, codeInvalidInput, "raid_group_id must reference a raid group of this guild")
+	case errors.Is(err, service.ErrForeignUser):
+		writeError(c, http.StatusBadRequest, codeInvalidInput, "user_id must reference a member of this guild")
+	case errors.Is(err, service.ErrUnplayableRole):
+		writeError(c, http.StatusBadRequest, codeInvalidInput, "preferred_role must be a role the class can play")
+	case errors.Is(err, service.ErrUnknownOffSpec):
+		writeError(c, http.StatusBadRequest, codeInvalidInput, "off_spec must be a spec of the class")
+	case errors.Is(err, service.ErrStaleLineup), errors.Is(err, service.ErrLineupLocked):
+		writeError(c, http.StatusConflict, codeConflict, err.Error())
+	case errors.Is(err, service.ErrEmptyLineup), errors.Is(err, service.ErrLineupFull),
+		errors.Is(err, service.ErrLineupDuplicate), errors.Is(err, service.ErrUnavailable):
+		writeError(c, http.StatusBadRequest, codeInvalidInput, err.Error())
+	case errors.As(err, &scheduleErr):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error":  "validation failed",
+			"code":   codeInvalidInput,
+			"fields": map[string]string{"schedule." + scheduleErr.Field: scheduleErr.Message},
+		})
+	default:
+		writeDBError(c, err)
+	}
+}
+
+// writeDBError maps storage errors to HTTP responses: missing rows become
+// 404, unique and foreign key violations 409, and CHECK/NOT NULL violations
+// or malformed IDs 400. Anything else is logged and returned as 500.
+func writeDBError(c *gin.Context, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, repository.ErrNotFound) {
+		writeError(c, http.StatusNotFound, codeNotFound, "resource not found")
+		return
+	}
+	if errors.Is(err, repository.ErrConflict) {
+		writeError(c, http.StatusConflict, codeConflict, "resource already exists or is still in use")
+		return
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgUniqueViolation:
+			writeError(c, http.StatusConflict, codeConflict, "resource already exists: "+pgErr.ConstraintName)
+			return
+		case pgForeignKeyViolation:
+			writeError(c, http.StatusConflict, codeConflict, "referenced resource is missing or still in use: "+pgErr.ConstraintName)
+			return
+		case pgCheckViolation, pgNotNullViolation:
+			writeError(c, http.StatusBadRequest, codeInvalidInput, "constraint violated: "+pgErr.ConstraintName)
+			return
+		case pgInvalidTextRepr:
+			writeError(c, http.StatusBadRequest, codeInvalidInput, "malformed identifier")
+			return
+		}
+	}
+
+	slog.ErrorContext(c.Request.Context(), "database error", "method", c.Request.Method, "route", c.FullPath(), "error", err)
+	writeError(c, http.StatusInternalServerError, codeInternal, "internal server error")
+}
@@ -0,0 +1,219 @@
+package api
+
+import (
+	"cmp"
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/composition"
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+)
+
+// rsvpEntry is a character with its answer to the event, if any.
+type rsvpEntry struct {
+	Character    models.Character
+	Confirmation *models.Confirmation
+}
+
+// Status is the answer of the character, pending when there is none.
+func (e rsvpEntry) Status() string {
+	if e.Confirmation == nil {
+		return models.ConfirmationPending
+	}
+	return e.Confirmation.Status
+}
+
+// rsvpGroup lists the answers of the characters of one raid role.
+type rsvpGroup struct {
+	Role      string
+	Entries   []rsvpEntry
+	Confirmed int
+}
+
+// lineupGroup lists the characters of one lineup status.
+type lineupGroup struct {
+	Status     string
+	Characters []models.Character
+}
+
+// statusOrder sorts answers within a role group, most certain first.
+var statusOrder = []string{
+	models.ConfirmationConfirmed,
+	models.ConfirmationTentative,
+	models.ConfirmationPending,
+	models.ConfirmationDeclined,
+}
+
+// rsvpChoices are the answers offered as buttons, in display order.
+var rsvpChoices = []string{
+	models.ConfirmationConfirmed,
+	models.ConfirmationTentative,
+	models.ConfirmationDeclined,
+}
+
+// event shows an event with its RSVPs grouped by raid role, and buttons to
+// answer for the user's characters.
+func (h *pageHandler) event(c *gin.Context) {
+	event, ok := h.loadPageEvent(c)
+	if !ok {
+		return
+	}
+	data, err := h.rsvpData(c, event)
+	if err != nil {
+		h.renderError(c, http.StatusInternalServerError, "The RSVPs could not be loaded.")
+		return
+	}
+	data["Title"] = event.RaidName
+	h.render(c, http.StatusOK, "pages/event.html", data)
+}
+
+// rsvp answers the event for one of the user's characters from the
+// character_id and status form fields. HTMX requests get the updated RSVP
+// section, plain form posts are redirected back to the event.
+func (h *pageHandler) rsvp(c *gin.Context) {
+	event, ok := h.loadPageEvent(c)
+	if !ok {
+		return
+	}
+	status := c.PostForm("status")
+	if !slices.Contains(models.ConfirmationStatuses, status) {
+		h.renderError(c, http.StatusBadRequest, "Pick confirmed, tentative or declined.")
+		return
+	}
+
+	ctx := c.Request.Context()
+	character, err := h.svc.Characters.Get(ctx, event.GuildID, c.PostForm("character_id"))
+	if errors.Is(err, repository.ErrNotFound) {
+		h.renderError(c, http.StatusBadRequest, "This character is not in the guild.")
+		return
+	}
+	if err != nil {
+		h.renderError(c, http.StatusInternalServerError, "The character could not be loaded.")
+		return
+	}
+	if character.UserID == nil || *character.UserID != pageUser(c).ID {
+		h.renderError(c, http.StatusForbidden, "You can only answer for your own characters.")
+		return
+	}
+
+	if _, _, err := h.svc.Confirmations.Respond(ctx, event, character, status, c.PostForm("reason")); err != nil {
+		h.renderError(c, http.StatusInternalServerError, "Your answer could not be saved.")
+		return
+	}
+
+	if !isHTMX(c) {
+		c.Redirect(http.StatusSeeOther, "/guilds/"+event.GuildID+"/events/"+event.ID)
+		return
+	}
+	data, err := h.rsvpData(c, event)
+	if err != nil {
+		h.renderError(c, http.StatusInternalServerError, "The RSVPs could not be loaded.")
+		return
+	}
+	h.render(c, http.StatusOK, "partials/rsvp.html", data)
+}
+
+// loadPageEvent loads :eventID within the guild of the request.
+func (h *pageHandler) loadPageEvent(c *gin.Context) (*models.Event, bool) {
+	event, err := h.svc.Events.Get(c.Request.Context(), pageGuild(c).ID, c.Param("eventID"))
+	if errors.Is(err, repository.ErrNotFound) {
+		h.renderError(c, http.StatusNotFound, "This event does not exist.")
+		return nil, false
+	}
+	if err != nil {
+		h.renderError(c, http.StatusInternalServerError, "The event could not be loaded.")
+		return nil, false
+	}
+	return event, true
+}
+
+// rsvpData groups the event's answers by the raid role of each character,
+// lists the user's characters still in the guild, and adds the event's
+// composition and lineup.
+func (h *pageHandler) rsvpData(c *gin.Context, event *models.Event) (gin.H, error) {
+	ctx := c.Request.Context()
+	confirmations, err := h.svc.Confirmations.List(ctx, event.ID, "")
+	if err != nil {
+		return nil, err
+	}
+	characters, err := h.svc.Characters.List(ctx, repository.CharacterFilter{GuildID: event.GuildID, IncludeLeft: true})
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*models.Character, len(characters))
+	for i := range characters {
+		byID[characters[i].ID] = &characters[i]
+	}
+	answers := make(map[string]*models.Confirmation, len(confirmations))
+
+	groups := make([]*rsvpGroup, len(models.RaidRoles)+1)
+	for i, role := range models.RaidRoles {
+		groups[i] = &rsvpGroup{Role: role}
+	}
+	groups[len(models.RaidRoles)] = &rsvpGroup{Role: ""}
+	for i := range confirmations {
+		confirmation := &confirmations[i]
+		answers[confirmation.CharacterID] = confirmation
+		character, ok := byID[confirmation.CharacterID]
+		if !ok {
+			continue
+		}
+		group := groups[roleRank(character.Role())]
+		group.Entries = append(group.Entries, rsvpEntry{Character: *character, Confirmation: confirmation})
+		if confirmation.Status == models.ConfirmationConfirmed {
+			group.Confirmed++
+		}
+	}
+	for _, group := range groups {
+		slices.SortFunc(group.Entries, func(a, b rsvpEntry) int {
+			return cmp.Or(
+				cmp.Compare(slices.Index(statusOrder, a.Status()), slices.Index(statusOrder, b.Status())),
+				cmp.Compare(a.Character.Name, b.Character.Name),
+			)
+		})
+	}
+	// Characters without a known role only get a group when they answered.
+	if len(groups[len(models.RaidRoles)].Entries) == 0 {
+		groups = groups[:len(models.RaidRoles)]
+	}
+
+	lineup, err := h.svc.Lineups.Get(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+	var lineupGroups []lineupGroup
+	for _, status := range models.LineupStatuses {
+		group := lineupGroup{Status: status}
+		for _, slot := range lineup.Slots {
+			if character, ok := byID[slot.CharacterID]; ok && slot.Status == status {
+				group.Characters = append(group.Characters, *character)
+			}
+		}
+		slices.SortFunc(group.Characters, func(a, b models.Character) int { return cmp.Compare(a.Name, b.Name) })
+		if len(group.Characters) > 0 {
+			lineupGroups = append(lineupGroups, group)
+		}
+	}
+
+	userID := pageUser(c).ID
+	var mine []rsvpEntry
+	for _, character := range characters {
+		if character.UserID != nil && *character.UserID == userID && character.LeftGuildAt == nil {
+			mine = append(mine, rsvpEntry{Character: character, Confirmation: answers[character.ID]})
+		}
+	}
+
+	return gin.H{
+		"Event":        event,
+		"Groups":       groups,
+		"Mine":         mine,
+		"Statuses":     rsvpChoices,
+		"Composition":  composition.Build(confirmations, characters),
+		"Lineup":       lineup,
+		"LineupGroups": lineupGroups,
+	}, nil
+}
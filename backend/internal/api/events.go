@@ -0,0 +1,144 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/auth"
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+	"github.com/GFerreiroS/guild-manager/backend/internal/service"
+)
+
+// eventRequest is the body for creating or replacing an event. When a raid
+// group is given, its characters get pending confirmations.
+type eventRequest struct {
+	RaidName    string     `json:"raid_name" binding:"required,max=255"`
+	Difficulty  string     `json:"difficulty" binding:"required,difficulty"`
+	ScheduledAt time.Time  `json:"scheduled_at" binding:"required"`
+	EndsAt      *time.Time `json:"ends_at" binding:"omitempty,gtfield=ScheduledAt"`
+	RaidGroupID *string    `json:"raid_group_id" binding:"omitempty,uuid"`
+}
+
+func (r eventRequest) input() service.EventInput {
+	return service.EventInput{
+		RaidName:    r.RaidName,
+		Difficulty:  r.Difficulty,
+		ScheduledAt: r.ScheduledAt,
+		EndsAt:      r.EndsAt,
+		RaidGroupID: r.RaidGroupID,
+	}
+}
+
+// listEvents lists the guild's events, optionally within ?from= and ?to=
+// (RFC 3339 timestamps).
+func (h *resourceHandler) listEvents(c *gin.Context) {
+	filter := repository.EventFilter{GuildID: currentGuild(c).ID}
+	for param, bound := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		s := c.Query(param)
+		if s == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			writeError(c, http.StatusBadRequest, codeInvalidInput, param+" must be an RFC 3339 timestamp")
+			return
+		}
+		*bound = t
+	}
+	page, ok := paginate(c)
+	if !ok {
+		return
+	}
+	filter.Page = page
+
+	events, err := h.svc.Events.List(c.Request.Context(), filter)
+	if err != nil {
+		writeDBError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": events})
+}
+
+func (h *resourceHandler) createEvent(c *gin.Context) {
+	var req eventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	userID := auth.CurrentSession(c).UserID
+	event, err := h.svc.Events.Create(c.Request.Context(), currentGuild(c).ID, userID, req.input())
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, event)
+}
+
+// getEvent returns an event together with its confirmations.
+func (h *resourceHandler) getEvent(c *gin.Context) {
+	event, err := h.svc.Events.GetWithConfirmations(c.Request.Context(), currentGuild(c).ID, c.Param("eventID"))
+	if err != nil {
+		writeDBError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, event)
+}
+
+// getEventComposition breaks down who confirmed or is tentative by role,
+// class and armor type, and flags missing raid buffs and utility.
+func (h *resourceHandler) getEventComposition(c *gin.Context) {
+	event, ok := h.loadEvent(c)
+	if !ok {
+		return
+	}
+	comp, err := h.svc.Events.Composition(c.Request.Context(), event)
+	if err != nil {
+		writeDBError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, comp)
+}
+
+func (h *resourceHandler) updateEvent(c *gin.Context) {
+	var req eventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+	event, ok := h.loadEvent(c)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.Events.Update(c.Request.Context(), event, req.input()); err != nil {
+		writeServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, event)
+}
+
+func (h *resourceHandler) deleteEvent(c *gin.Context) {
+	event, ok := h.loadEvent(c)
+	if !ok {
+		return
+	}
+	if err := h.svc.Events.Delete(c.Request.Context(), event); err != nil {
+		writeDBError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// loadEvent loads :eventID within the current guild.
+func (h *resourceHandler) loadEvent(c *gin.Context) (*models.Event, bool) {
+	event, err := h.svc.Events.Get(c.Request.Context(), currentGuild(c).ID, c.Param("eventID"))
+	if err != nil {
+		writeDBError(c, err)
+		return nil, false
+	}
+	return event, true
+}
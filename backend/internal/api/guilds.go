@@ -0,0 +1,130 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/auth"
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/service"
+	"github.com/GFerreiroS/guild-manager/backend/pkg/blizzard"
+)
+
+// guildRequest is the body for creating or replacing a guild. Region
+// defaults to the configured Battle.net region.
+type guildRequest struct {
+	Name    string `json:"name" binding:"required,max=255"`
+	Realm   string `json:"realm" binding:"required,max=255"`
+	Region  string `json:"region" binding:"omitempty,region"`
+	Faction string `json:"faction" binding:"required,faction"`
+}
+
+func (r guildRequest) input() service.GuildInput {
+	return service.GuildInput{Name: r.Name, Realm: r.Realm, Region: r.Region, Faction: r.Faction}
+}
+
+// listGuilds lists the caller's guilds, or every guild for site admins.
+func (h *resourceHandler) listGuilds(c *gin.Context) {
+	page, ok := paginate(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	user, err := h.svc.Users.Get(ctx, auth.CurrentSession(c).UserID)
+	if err != nil {
+		writeDBError(c, err)
+		return
+	}
+	var guilds []models.Guild
+	if user.Role == models.RoleAdmin {
+		guilds, err = h.svc.Guilds.List(ctx, page)
+	} else {
+		// Users are in a handful of guilds, so they are paged in memory.
+		guilds, err = h.svc.Guilds.ListForUser(ctx, user.ID)
+		guilds = guilds[min(page.Offset, len(guilds)):]
+		guilds = guilds[:min(page.Limit, len(guilds))]
+	}
+	if err != nil {
+		writeDBError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": guilds})
+}
+
+// createGuild creates a guild and makes the caller its guild master. Guilds
+// that exist on Battle.net have to be imported instead.
+func (h *resourceHandler) createGuild(c *gin.Context) {
+	var req guildRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+	if h.onBattleNet(c, req) {
+		return
+	}
+
+	guild, err := h.svc.Guilds.Create(c.Request.Context(), auth.CurrentSession(c).UserID, req.input())
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, guild)
+}
+
+func (h *resourceHandler) getGuild(c *gin.Context) {
+	c.JSON(http.StatusOK, currentGuild(c))
+}
+
+func (h *resourceHandler) updateGuild(c *gin.Context) {
+	var req guildRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	guild := currentGuild(c)
+	renamed := !strings.EqualFold(req.Name, guild.Name) || blizzard.Slug(req.Realm) != blizzard.Slug(guild.Realm)
+	if renamed && h.onBattleNet(c, req) {
+		return
+	}
+	if err := h.svc.Guilds.Update(c.Request.Context(), guild, req.input()); err != nil {
+		writeServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, guild)
+}
+
+// onBattleNet answers 409 and reports true when the guild of req exists on
+// Battle.net. Such guilds only get a guild master through the roster import,
+// which checks that they lead it in game, or from a site admin. Without
+// Blizzard API credentials nothing can be checked, nor imported.
+func (h *resourceHandler) onBattleNet(c *gin.Context, req guildRequest) bool {
+	if h.roster == nil || (req.Region != "" && !strings.EqualFold(req.Region, h.roster.Region())) {
+		return false
+	}
+
+	exists, err := h.roster.Exists(c.Request.Context(), req.Name, req.Realm)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "failed to look up guild on Battle.net", "guild", req.Name, "realm", req.Realm, "error", err)
+		writeError(c, http.StatusBadGateway, codeUnavailable, "failed to look up the guild on Battle.net")
+		return true
+	}
+	if exists {
+		writeError(c, http.StatusConflict, codeConflict, "guild exists on Battle.net; import it with POST /api/guilds/import")
+	}
+	return exists
+}
+
+// deleteGuild removes a guild; its characters, raid groups and events go with
+// it through ON DELETE CASCADE.
+func (h *resourceHandler) deleteGuild(c *gin.Context) {
+	if err := h.svc.Guilds.Delete(c.Request.Context(), currentGuild(c)); err != nil {
+		writeDBError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
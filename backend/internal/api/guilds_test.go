@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+)
+
+func TestListGuilds(t *testing.T) {
+	s := newTestServer(t)
+	admin, adminCookie := s.user(t, "admin", models.RoleAdmin)
+	member, memberCookie := s.user(t, "member", models.RoleMember)
+	s.guild(t, "Admins", admin)
+	own := s.guild(t, "Members", member)
+
+	tests := []struct {
+		name   string
+		cookie *http.Cookie
+		want   int
+	}{
+		{"admin sees every guild", adminCookie, 2},
+		{"member sees their guilds", memberCookie, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := s.do(t, http.MethodGet, "/api/v1/guilds", tt.cookie, nil)
+			wantStatus(t, w, http.StatusOK)
+
+			var resp struct {
+				Data []models.Guild `json:"data"`
+			}
+			decode(t, w, &resp)
+			if len(resp.Data) != tt.want {
+				t.Fatalf("got %d guilds, want %d", len(resp.Data), tt.want)
+			}
+		})
+	}
+
+	w := s.do(t, http.MethodGet, "/api/v1/guilds", memberCookie, nil)
+	var resp struct {
+		Data []models.Guild `json:"data"`
+	}
+	decode(t, w, &resp)
+	if resp.Data[0].ID != own.ID {
+		t.Fatalf("member got guild %s, want %s", resp.Data[0].ID, own.ID)
+	}
+
+	wantStatus(t, s.do(t, http.MethodGet, "/api/v1/guilds", nil, nil), http.StatusUnauthorized)
+}
+
+func TestGetGuildPermissions(t *testing.T) {
+	s := newTestServer(t)
+	master, masterCookie := s.user(t, "master", models.RoleMember)
+	_, outsiderCookie := s.user(t, "outsider", models.RoleMember)
+	_, adminCookie := s.user(t, "admin", models.RoleAdmin)
+	guild := s.guild(t, "Guild", master)
+	missing := "00000000-0000-4000-8000-000000000000"
+
+	tests := []struct {
+		name    string
+		cookie  *http.Cookie
+		guildID string
+		want    int
+	}{
+		{"member", masterCookie, guild.ID, http.StatusOK},
+		{"admin", adminCookie, guild.ID, http.StatusOK},
+		{"admin on missing guild", adminCookie, missing, http.StatusNotFound},
+		// Outsiders cannot tell whether a guild exists.
+		{"outsider", outsiderCookie, guild.ID, http.StatusForbidden},
+		{"outsider on missing guild", outsiderCookie, missing, http.StatusForbidden},
+		{"malformed guild ID", outsiderCookie, "not-a-uuid", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := s.do(t, http.MethodGet, "/api/v1/guilds/"+tt.guildID, tt.cookie, nil)
+			wantStatus(t, w, tt.want)
+		})
+	}
+}
+
+func TestGuildMemberRoles(t *testing.T) {
+	s := newTestServer(t)
+	master, _ := s.user(t, "master", models.RoleMember)
+	member, memberCookie := s.user(t, "member", models.RoleMember)
+	guild := s.guild(t, "Guild", master)
+	err := s.store.Guilds().AddMember(context.Background(), &models.GuildMember{GuildID: guild.ID, UserID: member.ID, Role: models.GuildRoleMember})
+	if err != nil {
+		t.Fatalf("add member: %v", err)
+	}
+
+	wantStatus(t, s.do(t, http.MethodGet, "/api/v1/guilds/"+guild.ID+"/members", memberCookie, nil), http.StatusOK)
+
+	w := s.do(t, http.MethodPost, "/api/v1/guilds/"+guild.ID+"/raid-groups", memberCookie, map[string]any{"name": "Main"})
+	wantStatus(t, w, http.StatusForbidden)
+	var resp struct {
+		Reason string `json:"reason"`
+	}
+	decode(t, w, &resp)
+	if resp.Reason != "insufficient_role" {
+		t.Fatalf("reason = %q, want insufficient_role", resp.Reason)
+	}
+}
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	goredis "github.com/go-redis/redis/v8"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/database"
+)
+
+// checkTimeout bounds every readiness check, so a hung dependency fails its
+// check instead of hanging the probe.
+const checkTimeout = 2 * time.Second
+
+// checkResult is the outcome of one readiness check.
+type checkResult struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+	// Migration details, only set by the migrations check.
+	Version *uint `json:"version,omitempty"`
+	Latest  *uint `json:"latest,omitempty"`
+	Dirty   *bool `json:"dirty,omitempty"`
+}
+
+// readinessCheck reports whether a dependency is usable. It may fill in
+// details on result.
+type readinessCheck func(ctx context.Context, result *checkResult) error
+
+type healthHandler struct {
+	checks map[string]readinessCheck
+}
+
+// RegisterHealthRoutes registers the probes. /livez only tells whether the
+// process serves requests; /readyz checks every dependency. /health is kept
+// as an alias of /readyz for existing monitors.
+func RegisterHealthRoutes(router *gin.Engine, db *sql.DB, rdb *goredis.Client) {
+	h := &healthHandler{checks: map[string]readinessCheck{
+		"postgres": func(ctx context.Context, _ *checkResult) error {
+			return db.PingContext(ctx)
+		},
+		"redis": func(ctx context.Context, _ *checkResult) error {
+			return rdb.Ping(ctx).Err()
+		},
+		"migrations": func(ctx context.Context, result *checkResult) error {
+			return checkMigrations(ctx, db, result)
+		},
+	}}
+
+	router.GET("/livez", h.livez)
+	router.GET("/readyz", h.readyz)
+	router.GET("/health", h.readyz)
+}
+
+func (h *healthHandler) livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok", "timestamp": time.Now().Format(time.RFC3339)})
+}
+
+// readyz runs the checks concurrently and answers 503 if any fails.
+func (h *healthHandler) readyz(c *gin.Context) {
+	results := make(map[string]*checkResult, len(h.checks))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range h.checks {
+		wg.Add(1)
+		go func(name string, check readinessCheck) {
+			defer wg.Done()
+			result := runCheck(c.Request.Context(), check)
+			mu.Lock()
+			results[name] = result
+			mu.Unlock()
+		}(name, check)
+	}
+	wg.Wait()
+
+	status, code := "ok", http.StatusOK
+	for _, r := range results {
+		if r.Status != "up" {
+			status, code = "unavailable", http.StatusServiceUnavailable
+		}
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(code, gin.H{
+		"status":    status,
+		"timestamp": time.Now().Format(time.RFC3339),
+		"checks":    results,
+	})
+}
+
+// runCheck runs check with checkTimeout. The check runs in its own goroutine
+// so even one that ignores its context cannot outlive the timeout.
+func runCheck(ctx context.Context, check readinessCheck) *checkResult {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	result := &checkResult{}
+	done := make(chan error, 1)
+	start := time.Now()
+	go func() {
+		done <- check(ctx, result)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = errors.New("timed out after " + checkTimeout.String())
+		// The check may still write to result; report a copy.
+		result = &checkResult{}
+	}
+	result.LatencyMS = float64(time.Since(start).Microseconds()) / 1000
+	result.Status = "up"
+	if err != nil {
+		result.Status, result.Error = "down", err.Error()
+	}
+	return result
+}
+
+// checkMigrations fails while migrations are dirty or behind the binary.
+func checkMigrations(ctx context.Context, db *sql.DB, result *checkResult) error {
+	status, err := database.SchemaStatus(ctx, db)
+	if err != nil {
+		return err
+	}
+	latest, err := database.LatestVersion()
+	if err != nil {
+		return err
+	}
+
+	version, dirty := status.Version, status.Dirty
+	result.Version, result.Latest, result.Dirty = &version, &latest, &dirty
+	switch {
+	case status.Dirty:
+		return errors.New("migration is dirty; fix it with the migrate force command")
+	case len(status.Pending) > 0:
+		return errors.New("migrations are behind")
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/auth"
+	"github.com/GFerreiroS/guild-manager/backend/internal/service"
+)
+
+// lineupRequest replaces an event's lineup. Characters in none of the lists
+// are left out of it.
+type lineupRequest struct {
+	// Version is the version the client edited, to detect concurrent edits.
+	Version  *int     `json:"version" binding:"omitempty,min=0"`
+	Selected []string `json:"selected" binding:"dive,uuid"`
+	Standby  []string `json:"standby" binding:"dive,uuid"`
+	Bench    []string `json:"bench" binding:"dive,uuid"`
+}
+
+func (r lineupRequest) input() service.LineupInput {
+	return service.LineupInput{
+		Version:  r.Version,
+		Selected: r.Selected,
+		Standby:  r.Standby,
+		Bench:    r.Bench,
+	}
+}
+
+func (h *resourceHandler) getLineup(c *gin.Context) {
+	event, ok := h.loadEvent(c)
+	if !ok {
+		return
+	}
+	lineup, err := h.svc.Lineups.Get(c.Request.Context(), event)
+	if err != nil {
+		writeDBError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, lineup)
+}
+
+// putLineup replaces the event's lineup. Changes to a locked lineup are
+// flagged in its history and notified to the characters' owners.
+func (h *resourceHandler) putLineup(c *gin.Context) {
+	var req lineupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+	event, ok := h.loadEvent(c)
+	if !ok {
+		return
+	}
+
+	userID := auth.CurrentSession(c).UserID
+	lineup, err := h.svc.Lineups.Save(c.Request.Context(), event, userID, req.input())
+	if errors.Is(err, service.ErrForeignCharacter) {
+		writeError(c, http.StatusBadRequest, codeInvalidInput, "selected, standby and bench must reference characters of this guild")
+		return
+	}
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, lineup)
+}
+
+// lockLineup locks the event's lineup and notifies the selected characters'
+// owners.
+func (h *resourceHandler) lockLineup(c *gin.Context) {
+	event, ok := h.loadEvent(c)
+	if !ok {
+		return
+	}
+
+	userID := auth.CurrentSession(c).UserID
+	lineup, err := h.svc.Lineups.Lock(c.Request.Context(), event, userID)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, lineup)
+}
+
+// lineupHistory lists every change of the event's lineup, oldest first.
+func (h *resourceHandler) lineupHistory(c *gin.Context) {
+	event, ok := h.loadEvent(c)
+	if !ok {
+		return
+	}
+	changes, err := h.svc.Lineups.History(c.Request.Context(), event)
+	if err != nil {
+		writeDBError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": changes})
+}
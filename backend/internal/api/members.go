@@ -0,0 +1,45 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// memberRequest is the body for adding a member or changing their role.
+type memberRequest struct {
+	Role string `json:"role" binding:"required,oneof=member officer guild-master"`
+}
+
+func (h *resourceHandler) listMembers(c *gin.Context) {
+	members, err := h.svc.Guilds.Members(c.Request.Context(), currentGuild(c).ID)
+	if err != nil {
+		writeDBError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": members})
+}
+
+// putMember adds a user to the guild or changes their role.
+func (h *resourceHandler) putMember(c *gin.Context) {
+	var req memberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	member, err := h.svc.Guilds.PutMember(c.Request.Context(), currentGuild(c).ID, c.Param("userID"), req.Role)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, member)
+}
+
+func (h *resourceHandler) deleteMember(c *gin.Context) {
+	if err := h.svc.Guilds.RemoveMember(c.Request.Context(), currentGuild(c).ID, c.Param("userID")); err != nil {
+		writeServiceError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
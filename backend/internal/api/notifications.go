@@ -0,0 +1,37 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/auth"
+)
+
+// listNotifications lists the caller's notifications, newest first, only
+// unread ones with ?unread=true.
+func (h *resourceHandler) listNotifications(c *gin.Context) {
+	page, ok := paginate(c)
+	if !ok {
+		return
+	}
+
+	userID := auth.CurrentSession(c).UserID
+	notifications, err := h.svc.Notifications.List(c.Request.Context(), userID, c.Query("unread") == "true", page)
+	if err != nil {
+		writeDBError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": notifications})
+}
+
+// readNotification marks one of the caller's notifications as read.
+func (h *resourceHandler) readNotification(c *gin.Context) {
+	userID := auth.CurrentSession(c).UserID
+	notification, err := h.svc.Notifications.MarkRead(c.Request.Context(), userID, c.Param("notificationID"))
+	if err != nil {
+		writeDBError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, notification)
+}
@@ -0,0 +1,174 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/auth"
+	"github.com/GFerreiroS/guild-manager/backend/internal/middleware"
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+	"github.com/GFerreiroS/guild-manager/backend/internal/service"
+)
+
+// pageUserContextKey holds the signed-in *models.User of a page request.
+const pageUserContextKey = "page_user"
+
+// pageHandler serves the server-rendered web UI. Pages render the templates
+// of internal/web; HTMX requests get only the partial they swap in.
+type pageHandler struct {
+	svc      *service.Services
+	sessions *auth.SessionStore
+	authz    *middleware.Authorizer
+}
+
+// registerPageRoutes registers the web UI. Unlike the API, pages redirect
+// anonymous visitors to the Battle.net login and render errors as HTML.
+func registerPageRoutes(router *gin.Engine, deps Dependencies) {
+	h := &pageHandler{svc: deps.Services, sessions: deps.Auth.Sessions(), authz: deps.Authz}
+
+	pages := router.Group("/", h.requireUser)
+	pages.GET("", h.home)
+
+	guild := pages.Group("/guilds/:id")
+	guild.GET("/roster", h.requireGuild(middleware.PermViewGuild), h.roster)
+	guild.GET("/calendar", h.requireGuild(middleware.PermViewGuild), h.calendar)
+	guild.GET("/events/:eventID", h.requireGuild(middleware.PermViewGuild), h.event)
+	guild.POST("/events/:eventID/rsvp", h.requireGuild(middleware.PermRSVP), h.rsvp)
+}
+
+// requireUser loads the session's user, or sends the visitor to the login.
+func (h *pageHandler) requireUser(c *gin.Context) {
+	sess, err := h.sessions.FromRequest(c)
+	if errors.Is(err, auth.ErrNoSession) {
+		h.redirectToLogin(c)
+		return
+	}
+	if err != nil {
+		h.renderError(c, http.StatusInternalServerError, "Your session could not be loaded.")
+		return
+	}
+
+	user, err := h.svc.Users.Get(c.Request.Context(), sess.UserID)
+	if errors.Is(err, repository.ErrNotFound) {
+		h.redirectToLogin(c)
+		return
+	}
+	if err != nil {
+		h.renderError(c, http.StatusInternalServerError, "Your account could not be loaded.")
+		return
+	}
+	c.Set(pageUserContextKey, user)
+	c.Next()
+}
+
+// redirectToLogin redirects to the login, through HX-Redirect for HTMX
+// requests since HTMX would swap a plain redirect's target into the page.
+func (h *pageHandler) redirectToLogin(c *gin.Context) {
+	if isHTMX(c) {
+		c.Header("HX-Redirect", "/auth/login")
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	c.Redirect(http.StatusFound, "/auth/login")
+	c.Abort()
+}
+
+// requireGuild loads the guild of the :id parameter into the context once
+// the user is known to hold perm in it.
+func (h *pageHandler) requireGuild(perm middleware.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !middleware.ValidID(c.Param("id")) {
+			h.renderError(c, http.StatusNotFound, "This guild does not exist.")
+			return
+		}
+
+		ctx := c.Request.Context()
+		allowed, err := h.authz.Can(ctx, pageUser(c).ID, c.Param("id"), perm)
+		if err != nil {
+			h.renderError(c, http.StatusInternalServerError, "Your permissions could not be checked.")
+			return
+		}
+		if !allowed {
+			h.renderError(c, http.StatusForbidden, "You are not allowed to do this in this guild.")
+			return
+		}
+
+		guild, err := h.svc.Guilds.Get(ctx, c.Param("id"))
+		if errors.Is(err, repository.ErrNotFound) {
+			h.renderError(c, http.StatusNotFound, "This guild does not exist.")
+			return
+		}
+		if err != nil {
+			h.renderError(c, http.StatusInternalServerError, "The guild could not be loaded.")
+			return
+		}
+		c.Set(guildContextKey, guild)
+		c.Next()
+	}
+}
+
+// homeNotifications is how many unread notifications the home page shows.
+const homeNotifications = 10
+
+// home lists the user's unread notifications and guilds next to their
+// status.
+func (h *pageHandler) home(c *gin.Context) {
+	ctx := c.Request.Context()
+	guilds, err := h.svc.Guilds.ListForUser(ctx, pageUser(c).ID)
+	if err != nil {
+		h.renderError(c, http.StatusInternalServerError, "Your guilds could not be loaded.")
+		return
+	}
+	notifications, err := h.svc.Notifications.List(ctx, pageUser(c).ID, true, repository.Page{Limit: homeNotifications})
+	if err != nil {
+		h.renderError(c, http.StatusInternalServerError, "Your notifications could not be loaded.")
+		return
+	}
+	h.render(c, http.StatusOK, "pages/home.html", gin.H{
+		"Title":         "Guild Manager",
+		"Guilds":        guilds,
+		"Notifications": notifications,
+	})
+}
+
+// render executes a template with data plus what every page needs: the
+// user, the guild of the request if any, and the CSRF token.
+func (h *pageHandler) render(c *gin.Context, status int, tmpl string, data gin.H) {
+	data["User"] = pageUser(c)
+	if guild, ok := c.Get(guildContextKey); ok {
+		data["Guild"] = guild
+	}
+	c.HTML(status, tmpl, middleware.CSRFTemplateData(c, data))
+}
+
+// renderError renders the error page and aborts the request.
+func (h *pageHandler) renderError(c *gin.Context, status int, message string) {
+	h.render(c, status, "pages/error.html", gin.H{
+		"Title":   http.StatusText(status),
+		"Status":  status,
+		"Message": message,
+	})
+	c.Abort()
+}
+
+// pageUser returns the user loaded by requireUser.
+func pageUser(c *gin.Context) *models.User {
+	if user, ok := c.Get(pageUserContextKey); ok {
+		return user.(*models.User)
+	}
+	return nil
+}
+
+// pageGuild returns the guild loaded by requireGuild.
+func pageGuild(c *gin.Context) *models.Guild {
+	return c.MustGet(guildContextKey).(*models.Guild)
+}
+
+// isHTMX reports whether the request was issued by HTMX, which only needs
+// the partial it swaps in.
+func isHTMX(c *gin.Context) bool {
+	return c.GetHeader("HX-Request") == "true"
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/service"
+)
+
+// raidGroupRequest is the body for creating or replacing a raid group. When
+// CharacterIDs is present it replaces the group's membership.
+type raidGroupRequest struct {
+	Name         string               `json:"name" binding:"required,max=255"`
+	Schedule     *models.RaidSchedule `json:"schedule"`
+	CharacterIDs *[]string            `json:"character_ids" binding:"omitempty,dive,uuid"`
+}
+
+func (r raidGroupRequest) input() service.RaidGroupInput {
+	return service.RaidGroupInput{Name: r.Name, Schedule: r.Schedule, CharacterIDs: r.CharacterIDs}
+}
+
+func (h *resourceHandler) listRaidGroups(c *gin.Context) {
+	page, ok := paginate(c)
+	if !ok {
+		return
+	}
+
+	groups, err := h.svc.RaidGroups.List(c.Request.Context(), currentGuild(c).ID, page)
+	if err != nil {
+		writeDBError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": groups})
+}
+
+func (h *resourceHandler) createRaidGroup(c *gin.Context) {
+	var req raidGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	group, err := h.svc.RaidGroups.Create(c.Request.Context(), currentGuild(c).ID, req.input())
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, group)
+}
+
+func (h *resourceHandler) getRaidGroup(c *gin.Context) {
+	group, ok := h.loadRaidGroup(c, true)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, group)
+}
+
+func (h *resourceHandler) updateRaidGroup(c *gin.Context) {
+	var req raidGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+	group, ok := h.loadRaidGroup(c, false)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.RaidGroups.Update(c.Request.Context(), group, req.input()); err != nil {
+		writeServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, group)
+}
+
+func (h *resourceHandler) deleteRaidGroup(c *gin.Context) {
+	group, ok := h.loadRaidGroup(c, false)
+	if !ok {
+		return
+	}
+	if err := h.svc.RaidGroups.Delete(c.Request.Context(), group); err != nil {
+		writeDBError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// loadRaidGroup loads :raidGroupID within the current guild, optionally with
+// its characters.
+func (h *resourceHandler) loadRaidGroup(c *gin.Context, withCharacters bool) (*models.RaidGroup, bool) {
+	group, err := h.svc.RaidGroups.Get(c.Request.Context(), currentGuild(c).ID, c.Param("raidGroupID"), withCharacters)
+	if err != nil {
+		writeDBError(c, err)
+		return nil, false
+	}
+	return group, true
+}
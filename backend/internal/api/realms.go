@@ -0,0 +1,22 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// listRealms lists known realms, optionally of a single ?region=.
+func (h *resourceHandler) listRealms(c *gin.Context) {
+	page, ok := paginate(c)
+	if !ok {
+		return
+	}
+
+	realms, err := h.svc.Realms.List(c.Request.Context(), c.Query("region"), page)
+	if err != nil {
+		writeDBError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": realms})
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"cmp"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+)
+
+// rosterColumn is a sortable column header of the roster table.
+type rosterColumn struct {
+	Key    string
+	Label  string
+	URL    string
+	Active bool
+	Desc   bool
+}
+
+// rosterSorts compares characters by each sortable column. Ties are broken
+// by name.
+var rosterSorts = map[string]func(a, b *models.Character) int{
+	"name":  func(a, b *models.Character) int { return 0 },
+	"class": func(a, b *models.Character) int { return cmp.Compare(a.Class, b.Class) },
+	"spec":  func(a, b *models.Character) int { return cmp.Compare(a.Spec, b.Spec) },
+	"role": func(a, b *models.Character) int {
+		return cmp.Compare(roleRank(a.Role()), roleRank(b.Role()))
+	},
+	"ilvl": func(a, b *models.Character) int { return cmp.Compare(a.Ilvl, b.Ilvl) },
+}
+
+// rosterColumns are the sortable columns in display order.
+var rosterColumns = []struct{ key, label string }{
+	{"name", "Name"}, {"class", "Class"}, {"spec", "Spec"}, {"role", "Role"}, {"ilvl", "Item level"},
+}
+
+// roster shows the guild's characters, sorted by ?sort= (name, class, spec,
+// role or ilvl) in ?order= (asc or desc). HTMX requests get only the table.
+func (h *pageHandler) roster(c *gin.Context) {
+	guild := pageGuild(c)
+	characters, err := h.svc.Characters.List(c.Request.Context(), repository.CharacterFilter{GuildID: guild.ID})
+	if err != nil {
+		h.renderError(c, http.StatusInternalServerError, "The roster could not be loaded.")
+		return
+	}
+
+	sortKey := c.DefaultQuery("sort", "name")
+	compare, ok := rosterSorts[sortKey]
+	if !ok {
+		sortKey, compare = "name", rosterSorts["name"]
+	}
+	desc := c.Query("order") == "desc"
+	slices.SortStableFunc(characters, func(a, b models.Character) int {
+		n := compare(&a, &b)
+		if desc {
+			n = -n
+		}
+		if n == 0 {
+			n = strings.Compare(a.Name, b.Name)
+		}
+		return n
+	})
+
+	columns := make([]rosterColumn, len(rosterColumns))
+	for i, col := range rosterColumns {
+		active := col.key == sortKey
+		// Clicking the active column flips its order; item level starts
+		// with the highest.
+		nextDesc := col.key == "ilvl"
+		if active {
+			nextDesc = !desc
+		}
+		query := url.Values{"sort": {col.key}, "order": {"asc"}}
+		if nextDesc {
+			query.Set("order", "desc")
+		}
+		columns[i] = rosterColumn{
+			Key:    col.key,
+			Label:  col.label,
+			URL:    "/guilds/" + guild.ID + "/roster?" + query.Encode(),
+			Active: active,
+			Desc:   active && desc,
+		}
+	}
+
+	tmpl := "pages/roster.html"
+	if isHTMX(c) {
+		tmpl = "partials/roster_table.html"
+	}
+	h.render(c, http.StatusOK, tmpl, gin.H{
+		"Title":      guild.Name + " roster",
+		"Characters": characters,
+		"Columns":    columns,
+	})
+}
+
+// roleRank orders raid roles like models.RaidRoles, unknown roles last.
+func roleRank(role string) int {
+	if i := slices.Index(models.RaidRoles, role); i >= 0 {
+		return i
+	}
+	return len(models.RaidRoles)
+}
@@ -1,36 +1,69 @@
 package api
 
 import (
-	"net/http"
-	"time"
-
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
+	goredis "github.com/go-redis/redis/v8"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/auth"
+	"github.com/GFerreiroS/guild-manager/backend/internal/middleware"
+	"github.com/GFerreiroS/guild-manager/backend/internal/roster"
+	"github.com/GFerreiroS/guild-manager/backend/internal/service"
+	"github.com/GFerreiroS/guild-manager/backend/internal/syncer"
 )
 
-// RegisterRoutes registers your API endpoints.
-func RegisterRoutes(router *gin.Engine, db *gorm.DB) {
-	// Health check endpoint
-	router.GET("/health", func(c *gin.Context) {
-		// Check DB connection
-		sqlDB, err := db.DB()
-		dbStatus := "up"
-		if err != nil || sqlDB.Ping() != nil {
-			dbStatus = "down"
-		}
-		c.JSON(http.StatusOK, gin.H{
-			"status":    "ok",
-			"timestamp": time.Now().Format(time.RFC3339),
-			"db_status": dbStatus,
-		})
-	})
-
-	// Add more endpoints as needed.
-	// For example:
-	router.GET("/api/guild-status", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"message": "Guild status endpoint",
-			"data":    "Sample data here",
-		})
-	})
+// Dependencies groups everything the HTTP handlers need.
+type Dependencies struct {
+	Redis *goredis.Client
+	Auth  *auth.Handler
+	Authz *middleware.Authorizer
+	// Services hold the business rules behind the REST resources.
+	Services *service.Services
+	// Syncer and Roster are nil when Blizzard API credentials are not configured.
+	Syncer *syncer.Syncer
+	Roster *roster.Importer
+	// RateLimits are applied on top of the global rate limit.
+	RateLimits RateLimits
+	// PublicURL is the scheme and host calendar feed URLs are built on.
+	PublicURL string
+}
+
+// RateLimits are stricter per-route rate limits. Nil limits are not applied.
+type RateLimits struct {
+	// Auth guards the Battle.net login flow.
+	Auth gin.HandlerFunc
+	// Sync guards roster imports and manual syncs, which call the Blizzard API.
+	Sync gin.HandlerFunc
+}
+
+// orNext returns h, or a no-op handler when h is nil.
+func orNext(h gin.HandlerFunc) gin.HandlerFunc {
+	if h == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return h
+}
+
+// RegisterRoutes registers your API endpoints. The probes are registered
+// separately by RegisterHealthRoutes.
+func RegisterRoutes(router *gin.Engine, deps Dependencies) {
+	// Battle.net login, callback and logout
+	deps.Auth.RegisterRoutes(router.Group("/", orNext(deps.RateLimits.Auth)))
+
+	// Dashboard status, as HTML for HTMX and JSON for API clients
+	registerStatusRoutes(router, deps)
+
+	// Server-rendered web UI
+	registerPageRoutes(router, deps)
+
+	// Versioned REST resources
+	registerV1Routes(router, deps)
+
+	// ICS calendar feeds
+	registerCalendarRoutes(router, deps)
+
+	// Roster import and manual character sync
+	requireSession := deps.Auth.Sessions().RequireSession()
+	syncLimit := orNext(deps.RateLimits.Sync)
+	router.POST("/api/guilds/import", requireSession, syncLimit, importGuildHandler(deps.Services, deps.Authz, deps.Roster))
+	router.POST("/api/guilds/:id/sync", deps.Authz.RequireGuildPermission("id", middleware.PermSyncGuild), syncLimit, syncGuildHandler(deps.Syncer))
 }
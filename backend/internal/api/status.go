@@ -0,0 +1,76 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/auth"
+	"github.com/GFerreiroS/guild-manager/backend/internal/service"
+)
+
+// guildStatusResponse is the body of GET /api/guild-status, and the data of
+// the partials/status.html template.
+type guildStatusResponse struct {
+	SignedIn  bool                   `json:"signed_in"`
+	Guilds    []*service.GuildStatus `json:"guilds"`
+	UpdatedAt time.Time              `json:"updated_at"`
+}
+
+// statusHandler serves the dashboard status of the caller's guilds.
+type statusHandler struct {
+	svc      *service.Services
+	sessions *auth.SessionStore
+}
+
+func registerStatusRoutes(router *gin.Engine, deps Dependencies) {
+	h := &statusHandler{svc: deps.Services, sessions: deps.Auth.Sessions()}
+	router.GET("/api/guild-status", h.guildStatus)
+}
+
+// guildStatus summarizes every guild of the signed-in user. Anonymous
+// callers get an empty, signed-out status rather than 401, so the dashboard
+// can invite them to log in.
+func (h *statusHandler) guildStatus(c *gin.Context) {
+	ctx := c.Request.Context()
+	now := time.Now().UTC()
+	resp := guildStatusResponse{Guilds: []*service.GuildStatus{}, UpdatedAt: now}
+
+	sess, err := h.sessions.FromRequest(c)
+	if err != nil && !errors.Is(err, auth.ErrNoSession) {
+		writeError(c, http.StatusInternalServerError, codeInternal, "session lookup failed")
+		return
+	}
+	if sess != nil {
+		resp.SignedIn = true
+		guilds, err := h.svc.Guilds.ListForUser(ctx, sess.UserID)
+		if err != nil {
+			writeDBError(c, err)
+			return
+		}
+		for i := range guilds {
+			status, err := h.svc.Guilds.Status(ctx, &guilds[i], now)
+			if err != nil {
+				writeDBError(c, err)
+				return
+			}
+			resp.Guilds = append(resp.Guilds, status)
+		}
+	}
+
+	respond(c, http.StatusOK, "partials/status.html", resp)
+}
+
+// respond renders tmpl for HTMX requests and browsers that prefer HTML, and
+// writes data as JSON for everyone else.
+func respond(c *gin.Context, status int, tmpl string, data any) {
+	c.Header("Vary", "HX-Request, Accept")
+	if isHTMX(c) || c.NegotiateFormat(binding.MIMEJSON, binding.MIMEHTML) == binding.MIMEHTML {
+		c.HTML(status, tmpl, data)
+		return
+	}
+	c.JSON(status, data)
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/auth"
+	"github.com/GFerreiroS/guild-manager/backend/internal/middleware"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+	"github.com/GFerreiroS/guild-manager/backend/internal/roster"
+	"github.com/GFerreiroS/guild-manager/backend/internal/service"
+	"github.com/GFerreiroS/guild-manager/backend/internal/syncer"
+	"github.com/GFerreiroS/guild-manager/backend/pkg/blizzard"
+)
+
+// syncGuildHandler triggers an immediate character sync for a guild.
+// Permissions are enforced by the route's authorization middleware.
+func syncGuildHandler(s *syncer.Syncer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if s == nil {
+			writeError(c, http.StatusServiceUnavailable, codeUnavailable, "character sync is not configured")
+			return
+		}
+
+		guildID := c.Param("id")
+		result, err := s.SyncGuild(c.Request.Context(), guildID)
+		if errors.Is(err, syncer.ErrSyncRunning) {
+			writeError(c, http.StatusConflict, codeConflict, err.Error())
+			return
+		}
+		if errors.Is(err, repository.ErrNotFound) {
+			writeError(c, http.StatusNotFound, codeNotFound, "guild not found")
+			return
+		}
+		if err != nil {
+			slog.ErrorContext(c.Request.Context(), "guild sync failed", "guild_id", guildID, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "sync failed", "code": codeInternal, "result": result})
+			return
+		}
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// importGuildRequest is the body of POST /api/guilds/import.
+type importGuildRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Realm string `json:"realm" binding:"required"`
+}
+
+// importGuildHandler creates a guild from its in-game roster, or reconciles
+// the roster of an already imported guild, and reports the roster diff.
+func importGuildHandler(svc *service.Services, authz *middleware.Authorizer, importer *roster.Importer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if importer == nil {
+			writeError(c, http.StatusServiceUnavailable, codeUnavailable, "roster import is not configured")
+			return
+		}
+
+		var req importGuildRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			writeBindError(c, err)
+			return
+		}
+
+		ctx := c.Request.Context()
+		sess := auth.CurrentSession(c)
+
+		// Re-importing an existing guild is an officer-only roster sync,
+		// unless nobody leads the guild yet and the import claims it.
+		claimant := roster.Claimant{UserID: sess.UserID, Characters: sess.Characters}
+		existing, err := svc.Guilds.Find(ctx, importer.Region(), blizzard.Slug(req.Realm), req.Name)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			slog.ErrorContext(ctx, "failed to look up guild", "guild", req.Name, "realm", req.Realm, "error", err)
+			writeError(c, http.StatusInternalServerError, codeInternal, "failed to look up guild")
+			return
+		}
+		if existing != nil {
+			allowed, err := authz.Can(ctx, sess.UserID, existing.ID, middleware.PermSyncGuild)
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to check guild permission", "guild_id", existing.ID, "error", err)
+				writeError(c, http.StatusInternalServerError, codeInternal, "failed to check permissions")
+				return
+			}
+			if !allowed {
+				led, err := svc.Guilds.LedByOthers(ctx, existing.ID, sess.UserID)
+				if err != nil {
+					slog.ErrorContext(ctx, "failed to look up guild masters", "guild_id", existing.ID, "error", err)
+					writeError(c, http.StatusInternalServerError, codeInternal, "failed to look up guild")
+					return
+				}
+				if led {
+					writeError(c, http.StatusForbidden, codeForbidden, "guild already exists; only its officers can re-import it")
+					return
+				}
+				claimant.MustClaim = true
+			}
+		}
+
+		imported, err := importer.Import(ctx, req.Name, req.Realm, claimant)
+		if errors.Is(err, roster.ErrNotLeader) {
+			writeError(c, http.StatusForbidden, codeForbidden, "guild already exists; only its officers or its in-game leader can re-import it")
+			return
+		}
+		if blizzard.IsNotFound(err) {
+			writeError(c, http.StatusNotFound, codeNotFound, "guild not found on Battle.net")
+			return
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "roster import failed", "guild", req.Name, "realm", req.Realm, "error", err)
+			writeError(c, http.StatusBadGateway, codeUnavailable, "roster import failed")
+			return
+		}
+
+		status := http.StatusOK
+		if imported.Created {
+			status = http.StatusCreated
+		}
+		c.JSON(status, gin.H{"guild": imported.Guild, "roster": imported.Roster, "claimed": imported.Claimed})
+	}
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+	"github.com/GFerreiroS/guild-manager/backend/internal/roster"
+	"github.com/GFerreiroS/guild-manager/backend/pkg/blizzard"
+)
+
+// withBlizzard serves the Blizzard API from a fake that knows one guild,
+// The Horde on Silvermoon, led by Thrall.
+func withBlizzard(t *testing.T, store repository.Store, deps *Dependencies) {
+	realm := map[string]any{"name": "Silvermoon", "slug": "silvermoon"}
+	member := func(name string, rank int) map[string]any {
+		return map[string]any{
+			"character": map[string]any{"name": name, "level": 80, "realm": realm, "playable_class": map[string]any{"id": 7}},
+			"rank":      rank,
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "token", "expires_in": 3600})
+	})
+	mux.HandleFunc("GET /data/wow/guild/silvermoon/the-horde/roster", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"guild":   map[string]any{"name": "The Horde", "realm": realm, "faction": map[string]any{"type": "HORDE"}},
+			"members": []any{member("Thrall", 0), member("Garrosh", 1)},
+		})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client := blizzard.NewClient(blizzard.Config{
+		ClientID:     "id",
+		ClientSecret: "secret",
+		Region:       "eu",
+		APIBaseURL:   server.URL,
+		OAuthBaseURL: server.URL,
+		MaxRetries:   blizzard.NoRetries,
+	})
+	deps.Roster = roster.NewImporter(store, client)
+}
+
+// leader is the session key of the guild's in-game leader.
+var leader = blizzard.CharacterKey("Silvermoon", "Thrall")
+
+func TestImportGuildClaim(t *testing.T) {
+	importBody := map[string]any{"name": "The Horde", "realm": "Silvermoon"}
+
+	tests := []struct {
+		name       string
+		characters []string
+		want       int
+		wantClaim  bool
+	}{
+		{"in-game leader", []string{leader}, http.StatusCreated, true},
+		{"other member", []string{blizzard.CharacterKey("Silvermoon", "Garrosh")}, http.StatusCreated, false},
+		// The leader's name on another realm is another character.
+		{"namesake on another realm", []string{blizzard.CharacterKey("Draenor", "Thrall")}, http.StatusCreated, false},
+		{"no characters", nil, http.StatusCreated, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, withBlizzard)
+			user, cookie := s.user(t, "user", models.RoleMember, tt.characters...)
+
+			w := s.do(t, http.MethodPost, "/api/guilds/import", cookie, importBody)
+			wantStatus(t, w, tt.want)
+			var resp struct {
+				Guild   models.Guild `json:"guild"`
+				Claimed bool         `json:"claimed"`
+			}
+			decode(t, w, &resp)
+			if resp.Claimed != tt.wantClaim {
+				t.Fatalf("claimed = %v, want %v", resp.Claimed, tt.wantClaim)
+			}
+
+			_, err := s.store.Guilds().Member(context.Background(), resp.Guild.ID, user.ID)
+			if tt.wantClaim != (err == nil) {
+				t.Fatalf("membership of the importing user: %v", err)
+			}
+		})
+	}
+}
+
+func TestReimportGuild(t *testing.T) {
+	importBody := map[string]any{"name": "The Horde", "realm": "Silvermoon"}
+
+	t.Run("without a guild master", func(t *testing.T) {
+		s := newTestServer(t, withBlizzard)
+		_, firstCookie := s.user(t, "first", models.RoleMember)
+		_, otherCookie := s.user(t, "other", models.RoleMember)
+		_, leaderCookie := s.user(t, "leader", models.RoleMember, leader)
+
+		wantStatus(t, s.do(t, http.MethodPost, "/api/guilds/import", firstCookie, importBody), http.StatusCreated)
+		// Nobody but the in-game leader may touch an unclaimed guild.
+		wantStatus(t, s.do(t, http.MethodPost, "/api/guilds/import", firstCookie, importBody), http.StatusForbidden)
+		wantStatus(t, s.do(t, http.MethodPost, "/api/guilds/import", otherCookie, importBody), http.StatusForbidden)
+
+		w := s.do(t, http.MethodPost, "/api/guilds/import", leaderCookie, importBody)
+		wantStatus(t, w, http.StatusOK)
+		var resp struct {
+			Claimed bool `json:"claimed"`
+		}
+		decode(t, w, &resp)
+		if !resp.Claimed {
+			t.Fatal("the in-game leader did not claim the guild")
+		}
+	})
+
+	t.Run("with a guild master", func(t *testing.T) {
+		s := newTestServer(t, withBlizzard)
+		_, masterCookie := s.user(t, "master", models.RoleMember, leader)
+		_, outsiderCookie := s.user(t, "outsider", models.RoleMember, leader)
+		_, adminCookie := s.user(t, "admin", models.RoleAdmin)
+
+		wantStatus(t, s.do(t, http.MethodPost, "/api/guilds/import", masterCookie, importBody), http.StatusCreated)
+		wantStatus(t, s.do(t, http.MethodPost, "/api/guilds/import", masterCookie, importBody), http.StatusOK)
+		wantStatus(t, s.do(t, http.MethodPost, "/api/guilds/import", adminCookie, importBody), http.StatusOK)
+		// Proving leadership does not unseat a guild master.
+		wantStatus(t, s.do(t, http.MethodPost, "/api/guilds/import", outsiderCookie, importBody), http.StatusForbidden)
+	})
+}
+
+func TestCreateGuildOnBattleNet(t *testing.T) {
+	s := newTestServer(t, withBlizzard)
+	master, cookie := s.user(t, "master", models.RoleMember)
+	guild := s.guild(t, "Free Form", master)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   map[string]any
+		want   int
+	}{
+		{"create unknown guild", http.MethodPost, "/api/v1/guilds", map[string]any{"name": "Unknown", "realm": "Silvermoon", "faction": "horde"}, http.StatusCreated},
+		{"create real guild", http.MethodPost, "/api/v1/guilds", map[string]any{"name": "the horde", "realm": "Silvermoon", "faction": "horde"}, http.StatusConflict},
+		{"create real guild in another region", http.MethodPost, "/api/v1/guilds", map[string]any{"name": "The Horde", "realm": "Silvermoon", "region": "us", "faction": "horde"}, http.StatusCreated},
+		{"keep name", http.MethodPut, "/api/v1/guilds/" + guild.ID, map[string]any{"name": "Free Form", "realm": "Silvermoon", "faction": "alliance"}, http.StatusOK},
+		{"rename to real guild", http.MethodPut, "/api/v1/guilds/" + guild.ID, map[string]any{"name": "The Horde", "realm": "Silvermoon", "faction": "horde"}, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wantStatus(t, s.do(t, tt.method, tt.path, cookie, tt.body), tt.want)
+		})
+	}
+}
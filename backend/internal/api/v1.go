@@ -0,0 +1,138 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/middleware"
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+	"github.com/GFerreiroS/guild-manager/backend/internal/roster"
+	"github.com/GFerreiroS/guild-manager/backend/internal/service"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+
+	// guildContextKey holds the *models.Guild resolved from the :id parameter.
+	guildContextKey = "guild"
+)
+
+// resourceHandler serves the /api/v1 CRUD endpoints.
+type resourceHandler struct {
+	svc    *service.Services
+	roster *roster.Importer
+}
+
+// registerV1Routes registers the versioned REST resources. Everything below a
+// guild is nested under /guilds/:id so the guild scope is always explicit.
+func registerV1Routes(router *gin.Engine, deps Dependencies) {
+	registerValidators()
+	h := &resourceHandler{svc: deps.Services, roster: deps.Roster}
+
+	v1 := router.Group("/api/v1", deps.Auth.Sessions().RequireSession())
+	v1.GET("/realms", h.listRealms)
+	v1.GET("/guilds", h.listGuilds)
+	v1.POST("/guilds", h.createGuild)
+	v1.GET("/notifications", h.listNotifications)
+	v1.POST("/notifications/:notificationID/read", h.readNotification)
+
+	// can checks perm before loading the guild, so outsiders get the same
+	// 403 whether or not the guild exists.
+	can := func(perm middleware.Permission) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			if deps.Authz.Authorize(c, "id", perm) {
+				h.requireGuild(c)
+			}
+		}
+	}
+
+	guild := v1.Group("/guilds/:id")
+	guild.GET("", can(middleware.PermViewGuild), h.getGuild)
+	guild.PUT("", can(middleware.PermManageGuild), h.updateGuild)
+	guild.DELETE("", can(middleware.PermManageGuild), h.deleteGuild)
+
+	guild.GET("/members", can(middleware.PermViewGuild), h.listMembers)
+	guild.PUT("/members/:userID", can(middleware.PermManageOfficers), h.putMember)
+	guild.DELETE("/members/:userID", can(middleware.PermManageOfficers), h.deleteMember)
+
+	guild.GET("/characters", can(middleware.PermViewGuild), h.listCharacters)
+	guild.POST("/characters", can(middleware.PermManageCharacters), h.createCharacter)
+	guild.GET("/characters/:characterID", can(middleware.PermViewGuild), h.getCharacter)
+	guild.PUT("/characters/:characterID", can(middleware.PermManageCharacters), h.updateCharacter)
+	guild.DELETE("/characters/:characterID", can(middleware.PermManageCharacters), h.deleteCharacter)
+
+	guild.GET("/raid-groups", can(middleware.PermViewGuild), h.listRaidGroups)
+	guild.POST("/raid-groups", can(middleware.PermManageRaidGroups), h.createRaidGroup)
+	guild.GET("/raid-groups/:raidGroupID", can(middleware.PermViewGuild), h.getRaidGroup)
+	guild.PUT("/raid-groups/:raidGroupID", can(middleware.PermManageRaidGroups), h.updateRaidGroup)
+	guild.DELETE("/raid-groups/:raidGroupID", can(middleware.PermManageRaidGroups), h.deleteRaidGroup)
+
+	guild.GET("/events", can(middleware.PermViewGuild), h.listEvents)
+	guild.POST("/events", can(middleware.PermManageEvents), h.createEvent)
+	guild.GET("/events/:eventID", can(middleware.PermViewGuild), h.getEvent)
+	guild.GET("/events/:eventID/composition", can(middleware.PermViewGuild), h.getEventComposition)
+	guild.PUT("/events/:eventID", can(middleware.PermManageEvents), h.updateEvent)
+	guild.DELETE("/events/:eventID", can(middleware.PermManageEvents), h.deleteEvent)
+
+	// Members may only answer for their own characters; see createConfirmation.
+	guild.GET("/events/:eventID/confirmations", can(middleware.PermViewGuild), h.listConfirmations)
+	guild.POST("/events/:eventID/confirmations", can(middleware.PermRSVP), h.createConfirmation)
+	guild.GET("/events/:eventID/confirmations/:confirmationID", can(middleware.PermViewGuild), h.getConfirmation)
+	guild.PUT("/events/:eventID/confirmations/:confirmationID", can(middleware.PermRSVP), h.updateConfirmation)
+	guild.DELETE("/events/:eventID/confirmations/:confirmationID", can(middleware.PermManageEvents), h.deleteConfirmation)
+	guild.PUT("/events/:eventID/rsvp/:characterID", can(middleware.PermRSVP), h.respondForCharacter)
+
+	guild.GET("/events/:eventID/lineup", can(middleware.PermViewGuild), h.getLineup)
+	guild.PUT("/events/:eventID/lineup", can(middleware.PermManageEvents), h.putLineup)
+	guild.POST("/events/:eventID/lineup/lock", can(middleware.PermManageEvents), h.lockLineup)
+	guild.GET("/events/:eventID/lineup/history", can(middleware.PermViewGuild), h.lineupHistory)
+
+	guild.GET("/events/:eventID/attendance", can(middleware.PermViewGuild), h.listAttendance)
+	guild.PUT("/events/:eventID/attendance", can(middleware.PermRecordAttendance), h.putAttendance)
+	guild.DELETE("/events/:eventID/attendance/:characterID", can(middleware.PermRecordAttendance), h.deleteAttendance)
+
+	guild.GET("/reports/attendance", can(middleware.PermViewGuild), h.guildAttendanceReport)
+	guild.GET("/raid-groups/:raidGroupID/attendance", can(middleware.PermViewGuild), h.raidGroupAttendanceReport)
+}
+
+// requireGuild loads the guild named by :id, answering 404 if it is missing.
+func (h *resourceHandler) requireGuild(c *gin.Context) {
+	guild, err := h.svc.Guilds.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeDBError(c, err)
+		return
+	}
+	c.Set(guildContextKey, guild)
+	c.Next()
+}
+
+// currentGuild returns the guild loaded by requireGuild.
+func currentGuild(c *gin.Context) *models.Guild {
+	return c.MustGet(guildContextKey).(*models.Guild)
+}
+
+// paginate reads ?limit= and ?offset= into a page.
+func paginate(c *gin.Context) (repository.Page, bool) {
+	limit, offset := defaultPageSize, 0
+	if s := c.Query("limit"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v < 1 || v > maxPageSize {
+			writeError(c, http.StatusBadRequest, codeInvalidInput, "limit must be between 1 and "+strconv.Itoa(maxPageSize))
+			return repository.Page{}, false
+		}
+		limit = v
+	}
+	if s := c.Query("offset"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v < 0 {
+			writeError(c, http.StatusBadRequest, codeInvalidInput, "offset must be a non-negative integer")
+			return repository.Page{}, false
+		}
+		offset = v
+	}
+	return repository.Page{Limit: limit, Offset: offset}, true
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+)
+
+// enumValidators maps custom binding tags to the values allowed by the
+// matching database CHECK constraint.
+var enumValidators = map[string][]string{
+	"wowclass":   models.Classes,
+	"difficulty": models.Difficulties,
+	"faction":    models.Factions,
+	"rsvp":       models.ConfirmationStatuses,
+	"attendance": models.AttendanceStatuses,
+	"region":     models.Regions,
+	"raidrole":   models.RaidRoles,
+}
+
+var registerValidatorsOnce sync.Once
+
+// registerValidators adds the enum tags to gin's validator and makes
+// validation errors report JSON field names.
+func registerValidators() {
+	registerValidatorsOnce.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "" || name == "-" {
+				return field.Name
+			}
+			return name
+		})
+
+		for tag, allowed := range enumValidators {
+			allowed := allowed
+			_ = v.RegisterValidation(tag, func(fl validator.FieldLevel) bool {
+				return slices.Contains(allowed, fl.Field().String())
+			})
+		}
+	})
+}
+
+// validationMessage renders a single field error for API clients.
+func validationMessage(fe validator.FieldError) string {
+	if allowed, ok := enumValidators[fe.Tag()]; ok {
+		return "must be one of: " + strings.Join(allowed, ", ")
+	}
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "uuid":
+		return "must be a UUID"
+	case "max":
+		return fmt.Sprintf("must be at most %s", fe.Param())
+	case "min":
+		return fmt.Sprintf("must be at least %s", fe.Param())
+	case "gtfield":
+		return "must be after the start"
+	case "oneof":
+		return "must be one of: " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	}
+	return fmt.Sprintf("failed %q validation", fe.Tag())
+}
@@ -0,0 +1,58 @@
+// Package attendance computes attendance reports from the attendance
+// recorded for each character.
+package attendance
+
+import (
+	"math"
+	"time"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+)
+
+// CharacterStats is a character's attendance over the report's events.
+type CharacterStats struct {
+	repository.AttendanceCounts
+	AttendancePct float64 `json:"attendance_pct"`
+}
+
+// Report is the attendance of every character with a record in range.
+type Report struct {
+	From          time.Time        `json:"from"`
+	To            time.Time        `json:"to"`
+	RaidGroupID   string           `json:"raid_group_id,omitempty"`
+	AttendancePct float64          `json:"attendance_pct"`
+	Characters    []CharacterStats `json:"characters"`
+}
+
+// Build computes the report of the filter's counts. Present, late and
+// benched count as attended, absences against it, and excused events are
+// ignored.
+func Build(f repository.AttendanceFilter, counts []repository.AttendanceCounts) *Report {
+	report := &Report{From: f.From, To: f.To, RaidGroupID: f.RaidGroupID, Characters: make([]CharacterStats, len(counts))}
+	var attended, counted int
+	for i, c := range counts {
+		s := &report.Characters[i]
+		s.AttendanceCounts = c
+		s.AttendancePct = percentage(s.attended(), s.counted())
+		attended += s.attended()
+		counted += s.counted()
+	}
+	report.AttendancePct = percentage(attended, counted)
+	return report
+}
+
+func (s *CharacterStats) attended() int {
+	return s.Present + s.Late + s.Benched
+}
+
+func (s *CharacterStats) counted() int {
+	return s.attended() + s.Absent
+}
+
+// percentage rounds to one decimal; no counted events means 0.
+func percentage(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)*1000/float64(total)) / 10
+}
@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/oauth2"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+)
+
+// Handler serves the /auth endpoints.
+type Handler struct {
+	store    repository.Store
+	provider *Provider
+	sessions *SessionStore
+}
+
+// NewHandler creates a Handler.
+func NewHandler(store repository.Store, provider *Provider, sessions *SessionStore) *Handler {
+	return &Handler{store: store, provider: provider, sessions: sessions}
+}
+
+// Sessions exposes the session store used by the handler.
+func (h *Handler) Sessions() *SessionStore {
+	return h.sessions
+}
+
+// RegisterRoutes registers the login, callback and logout endpoints.
+func (h *Handler) RegisterRoutes(router gin.IRouter) {
+	group := router.Group("/auth")
+	group.GET("/login", h.Login)
+	group.GET("/callback", h.Callback)
+	group.POST("/logout", h.Logout)
+}
+
+// Login starts the authorization-code flow by redirecting to Battle.net.
+func (h *Handler) Login(c *gin.Context) {
+	state, err := randomToken(32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to start login"})
+		return
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	if err := h.sessions.SaveState(c.Request.Context(), state, verifier); err != nil {
+		slog.ErrorContext(c.Request.Context(), "failed to save oauth state", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to start login"})
+		return
+	}
+
+	c.Redirect(http.StatusFound, h.provider.AuthCodeURL(state, verifier))
+}
+
+// Callback completes the flow: it validates the state, exchanges the code,
+// upserts the user and starts a session holding the account's WoW
+// characters.
+func (h *Handler) Callback(c *gin.Context) {
+	if errParam := c.Query("error"); errParam != "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authorization denied: " + errParam})
+		return
+	}
+
+	state, code := c.Query("state"), c.Query("code")
+	if state == "" || code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "missing state or code"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	verifier, err := h.sessions.TakeState(ctx, state)
+	if errors.Is(err, ErrNoSession) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid or expired state"})
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to load oauth state", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "login failed"})
+		return
+	}
+
+	token, err := h.provider.Exchange(ctx, code, verifier)
+	if err != nil {
+		slog.WarnContext(ctx, "oauth exchange failed", "error", err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to exchange authorization code"})
+		return
+	}
+
+	bnetUser, err := h.provider.FetchUser(ctx, token)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to fetch Battle.net user", "error", err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to fetch Battle.net account"})
+		return
+	}
+
+	user := models.User{
+		BattleNetID: strconv.FormatInt(bnetUser.ID, 10),
+		Username:    bnetUser.BattleTag,
+	}
+	if err := h.store.Users().Upsert(ctx, &user); err != nil {
+		slog.ErrorContext(ctx, "failed to upsert user", "battle_net_id", user.BattleNetID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save user"})
+		return
+	}
+
+	// The characters only let the user claim guilds they lead, so logging
+	// in does not depend on the profile API.
+	characters, err := h.provider.FetchCharacters(ctx, token)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to fetch WoW characters", "battle_net_id", user.BattleNetID, "error", err)
+	}
+
+	_, cookie, err := h.sessions.Create(ctx, user.ID, user.BattleNetID, characters)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create session", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create session"})
+		return
+	}
+	h.sessions.SetCookie(c, cookie)
+
+	c.Redirect(http.StatusFound, "/")
+}
+
+// Logout destroys the current session.
+func (h *Handler) Logout(c *gin.Context) {
+	if cookie, err := c.Cookie(SessionCookie); err == nil && cookie != "" {
+		if err := h.sessions.Delete(c.Request.Context(), cookie); err != nil {
+			slog.ErrorContext(c.Request.Context(), "failed to delete session", "error", err)
+		}
+	}
+	h.sessions.ClearCookie(c)
+	c.JSON(http.StatusOK, gin.H{"status": "logged out"})
+}
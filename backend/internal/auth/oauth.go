@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/GFerreiroS/guild-manager/backend/pkg/blizzard"
+)
+
+// BattleNetUser is the subset of the Battle.net userinfo response we use.
+type BattleNetUser struct {
+	ID        int64  `json:"id"`
+	BattleTag string `json:"battletag"`
+}
+
+// Provider performs the Battle.net OAuth2 authorization-code flow.
+type Provider struct {
+	oauth       *oauth2.Config
+	userInfoURL string
+	profileURL  string
+	httpClient  *http.Client
+}
+
+// NewProvider creates a Provider. When oauthURL is empty the region's
+// Battle.net hosts are used; otherwise it points at a custom (e.g. fake)
+// server exposing /authorize, /token, /userinfo and /profile/user/wow.
+func NewProvider(clientID, clientSecret, redirectURL, region, oauthURL string) *Provider {
+	base := strings.TrimRight(oauthURL, "/")
+	apiBase := base
+	if base == "" {
+		base, apiBase = blizzard.OAuthHost(region), blizzard.APIHost(region)
+	}
+	region = strings.ToLower(region)
+	profileQuery := url.Values{"namespace": {"profile-" + region}, "locale": {"en_US"}}
+
+	return &Provider{
+		oauth: &oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			RedirectURL:  redirectURL,
+			Scopes:       []string{"openid", "wow.profile"},
+			Endpoint: oauth2.Endpoint{
+				AuthURL:   base + "/authorize",
+				TokenURL:  base + "/token",
+				AuthStyle: oauth2.AuthStyleInHeader,
+			},
+		},
+		userInfoURL: base + "/userinfo",
+		profileURL:  apiBase + "/profile/user/wow?" + profileQuery.Encode(),
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// AuthCodeURL returns the URL the user is redirected to for login, including
+// the state and the PKCE S256 challenge derived from verifier.
+func (p *Provider) AuthCodeURL(state, verifier string) string {
+	return p.oauth.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier))
+}
+
+// Exchange trades an authorization code for an access token.
+func (p *Provider) Exchange(ctx context.Context, code, verifier string) (*oauth2.Token, error) {
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, p.httpClient)
+	token, err := p.oauth.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+	return token, nil
+}
+
+// FetchUser loads the Battle.net account behind an access token.
+func (p *Provider) FetchUser(ctx context.Context, token *oauth2.Token) (*BattleNetUser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.userInfoURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build userinfo request: %w", err)
+	}
+	token.SetAuthHeader(req)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("userinfo request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("userinfo request returned status %d", resp.StatusCode)
+	}
+
+	var user BattleNetUser
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return nil, fmt.Errorf("failed to decode userinfo response: %w", err)
+	}
+	if user.ID == 0 {
+		return nil, fmt.Errorf("userinfo response is missing the account id")
+	}
+	return &user, nil
+}
+
+// FetchCharacters lists the WoW characters of the account behind an access
+// token as blizzard.CharacterKey values. An account without WoW characters
+// has none.
+func (p *Provider) FetchCharacters(ctx context.Context, token *oauth2.Token) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.profileURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build account profile request: %w", err)
+	}
+	token.SetAuthHeader(req)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("account profile request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Battle.net answers 404 for accounts that never played WoW.
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("account profile request returned status %d", resp.StatusCode)
+	}
+
+	var profile blizzard.AccountProfile
+	if err := json.NewDecoder(resp.Body).Decode(&profile); err != nil {
+		return nil, fmt.Errorf("failed to decode account profile response: %w", err)
+	}
+	var keys []string
+	for _, account := range profile.WowAccounts {
+		for _, character := range account.Characters {
+			keys = append(keys, blizzard.CharacterKey(character.Realm.Slug, character.Name))
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func TestFetchCharacters(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "characters of every account",
+			status: http.StatusOK,
+			body: `{"wow_accounts": [
+				{"id": 1, "characters": [{"name": "Thrall", "realm": {"name": "Silvermoon", "slug": "silvermoon"}}]},
+				{"id": 2, "characters": [{"name": "Jaina", "realm": {"name": "Kel'Thuzad", "slug": "kelthuzad"}}]}
+			]}`,
+			want: []string{"silvermoon/thrall", "kelthuzad/jaina"},
+		},
+		{name: "account without WoW", status: http.StatusNotFound},
+		{name: "API failure", status: http.StatusInternalServerError, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/profile/user/wow" || r.URL.Query().Get("namespace") != "profile-eu" ||
+					r.Header.Get("Authorization") != "Bearer user-token" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			p := NewProvider("id", "secret", "http://localhost/auth/callback", "EU", server.URL)
+			got, err := p.FetchCharacters(context.Background(), &oauth2.Token{AccessToken: "user-token", TokenType: "Bearer"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("characters = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
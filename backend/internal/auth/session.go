@@ -0,0 +1,223 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// SessionCookie is the name of the cookie carrying the signed session ID.
+	SessionCookie = "gm_session"
+
+	sessionKeyPrefix = "session:"
+	stateKeyPrefix   = "oauth_state:"
+	stateTTL         = 10 * time.Minute
+
+	// sessionContextKey is the gin context key holding the current *Session.
+	sessionContextKey = "session"
+)
+
+// ErrNoSession is returned when a request carries no valid session.
+var ErrNoSession = errors.New("no valid session")
+
+// Session is the server-side state behind a signed session cookie.
+type Session struct {
+	ID          string    `json:"-"`
+	UserID      string    `json:"user_id"`
+	BattleNetID string    `json:"battle_net_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	// Characters are the blizzard.CharacterKey of the WoW characters the
+	// account had at login, which prove who leads a guild in game.
+	Characters []string `json:"characters,omitempty"`
+}
+
+// SessionStore keeps sessions and pending OAuth states in Redis. The cookie
+// only holds the session ID plus an HMAC signature made with the session
+// secret, so a tampered cookie is rejected before Redis is consulted.
+type SessionStore struct {
+	rdb    *redis.Client
+	secret []byte
+	ttl    time.Duration
+	secure bool
+}
+
+// NewSessionStore creates a SessionStore.
+func NewSessionStore(rdb *redis.Client, secret string, ttl time.Duration, secure bool) *SessionStore {
+	return &SessionStore{
+		rdb:    rdb,
+		secret: []byte(secret),
+		ttl:    ttl,
+		secure: secure,
+	}
+}
+
+// Create stores a new session for the user, owning characters, and returns
+// it together with the signed cookie value.
+func (s *SessionStore) Create(ctx context.Context, userID, battleNetID string, characters []string) (*Session, string, error) {
+	id, err := randomToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sess := &Session{
+		ID:          id,
+		UserID:      userID,
+		BattleNetID: battleNetID,
+		Characters:  characters,
+		CreatedAt:   time.Now().UTC(),
+	}
+	payload, err := json.Marshal(sess)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode session: %w", err)
+	}
+	if err := s.rdb.Set(ctx, sessionKeyPrefix+id, payload, s.ttl).Err(); err != nil {
+		return nil, "", fmt.Errorf("failed to store session: %w", err)
+	}
+
+	return sess, id + "." + s.sign(id), nil
+}
+
+// Get resolves a signed cookie value to its session.
+func (s *SessionStore) Get(ctx context.Context, cookieValue string) (*Session, error) {
+	id, ok := s.verify(cookieValue)
+	if !ok {
+		return nil, ErrNoSession
+	}
+
+	payload, err := s.rdb.Get(ctx, sessionKeyPrefix+id).Bytes()
+	if err == redis.Nil {
+		return nil, ErrNoSession
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session: %w", err)
+	}
+
+	var sess Session
+	if err := json.Unmarshal(payload, &sess); err != nil {
+		return nil, fmt.Errorf("failed to decode session: %w", err)
+	}
+	sess.ID = id
+	return &sess, nil
+}
+
+// Delete removes the session behind a signed cookie value, if any.
+func (s *SessionStore) Delete(ctx context.Context, cookieValue string) error {
+	id, ok := s.verify(cookieValue)
+	if !ok {
+		return nil
+	}
+	return s.rdb.Del(ctx, sessionKeyPrefix+id).Err()
+}
+
+// FromRequest resolves the session attached to the request's cookie.
+func (s *SessionStore) FromRequest(c *gin.Context) (*Session, error) {
+	if sess, ok := c.Get(sessionContextKey); ok {
+		return sess.(*Session), nil
+	}
+
+	cookie, err := c.Cookie(SessionCookie)
+	if err != nil || cookie == "" {
+		return nil, ErrNoSession
+	}
+	sess, err := s.Get(c.Request.Context(), cookie)
+	if err != nil {
+		return nil, err
+	}
+	c.Set(sessionContextKey, sess)
+	return sess, nil
+}
+
+// RequireSession returns a middleware that rejects requests without a valid
+// session with 401.
+func (s *SessionStore) RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, err := s.FromRequest(c); err != nil {
+			if !errors.Is(err, ErrNoSession) {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "session lookup failed"})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// CurrentSession returns the session resolved earlier in the request, if any.
+func CurrentSession(c *gin.Context) *Session {
+	if sess, ok := c.Get(sessionContextKey); ok {
+		return sess.(*Session)
+	}
+	return nil
+}
+
+// SetCookie writes the session cookie on the response.
+func (s *SessionStore) SetCookie(c *gin.Context, value string) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(SessionCookie, value, int(s.ttl.Seconds()), "/", "", s.secure, true)
+}
+
+// ClearCookie expires the session cookie on the client.
+func (s *SessionStore) ClearCookie(c *gin.Context) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(SessionCookie, "", -1, "/", "", s.secure, true)
+}
+
+// SaveState remembers the PKCE verifier for a pending login.
+func (s *SessionStore) SaveState(ctx context.Context, state, verifier string) error {
+	if err := s.rdb.Set(ctx, stateKeyPrefix+state, verifier, stateTTL).Err(); err != nil {
+		return fmt.Errorf("failed to store oauth state: %w", err)
+	}
+	return nil
+}
+
+// TakeState returns and deletes the PKCE verifier for a pending login, so
+// each state can only be used once.
+func (s *SessionStore) TakeState(ctx context.Context, state string) (string, error) {
+	verifier, err := s.rdb.GetDel(ctx, stateKeyPrefix+state).Result()
+	if err == redis.Nil {
+		return "", ErrNoSession
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to load oauth state: %w", err)
+	}
+	return verifier, nil
+}
+
+func (s *SessionStore) sign(id string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(id))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (s *SessionStore) verify(cookieValue string) (string, bool) {
+	id, sig, ok := strings.Cut(cookieValue, ".")
+	if !ok || id == "" {
+		return "", false
+	}
+	if !hmac.Equal([]byte(sig), []byte(s.sign(id))) {
+		return "", false
+	}
+	return id, true
+}
+
+// randomToken returns n random bytes encoded as URL-safe base64.
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
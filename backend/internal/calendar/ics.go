@@ -0,0 +1,120 @@
+// Package calendar renders raid events as iCalendar (RFC 5545) feeds.
+package calendar
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	prodID = "-//guild-manager//raid calendar//EN"
+	// uidDomain makes event UIDs globally unique while staying stable, so
+	// calendar clients replace an updated event instead of adding a copy.
+	uidDomain = "guild-manager"
+	// maxLineOctets is the longest content line allowed before folding.
+	maxLineOctets = 75
+	// utcLayout renders times as UTC DATE-TIME values, which every client
+	// converts to the viewer's time zone without needing a VTIMEZONE.
+	utcLayout = "20060102T150405Z"
+)
+
+// Event is a single VEVENT.
+type Event struct {
+	ID          string
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+	Modified    time.Time
+	// Sequence is the event's revision, so clients know which copy is newer.
+	Sequence int
+}
+
+// Calendar is a VCALENDAR with its events.
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+// UID returns the stable iCalendar UID of an event.
+func UID(eventID string) string {
+	return eventID + "@" + uidDomain
+}
+
+// Render encodes the calendar, stamping it with now.
+func Render(cal Calendar, now time.Time) []byte {
+	var buf bytes.Buffer
+	line := func(name, value string) {
+		writeLine(&buf, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", prodID)
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", escape(cal.Name))
+	for _, e := range cal.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", UID(e.ID))
+		line("DTSTAMP", now.UTC().Format(utcLayout))
+		line("DTSTART", e.Start.UTC().Format(utcLayout))
+		line("DTEND", e.End.UTC().Format(utcLayout))
+		line("SUMMARY", escape(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION", escape(e.Description))
+		}
+		line("LAST-MODIFIED", e.Modified.UTC().Format(utcLayout))
+		line("SEQUENCE", fmt.Sprint(e.Sequence))
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return buf.Bytes()
+}
+
+// NewFeedToken returns a random URL-safe token for calendar feed URLs.
+func NewFeedToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate feed token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// escape escapes a TEXT value.
+func escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// writeLine writes a content line terminated by CRLF, folding it so that no
+// line exceeds 75 octets without splitting a UTF-8 sequence.
+func writeLine(buf *bytes.Buffer, s string) {
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(s[cut]) {
+			cut--
+		}
+		buf.WriteString(s[:cut])
+		buf.WriteString("\r\n ")
+		s = s[cut:]
+		// Continuation lines start with a space that counts towards the limit.
+		limit = maxLineOctets - 1
+	}
+	buf.WriteString(s)
+	buf.WriteString("\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
@@ -0,0 +1,159 @@
+// Package composition summarizes who answered an event: how many characters
+// fill each raid role, class and armor type, and which raid buffs and
+// utilities nobody confirmed brings.
+package composition
+
+import "github.com/GFerreiroS/guild-manager/backend/internal/models"
+
+// Provider is a raid buff or utility and the classes that bring it.
+type Provider struct {
+	Key     string   `json:"key"`
+	Name    string   `json:"name"`
+	Classes []string `json:"classes"`
+}
+
+// Buffs are the raid-wide buffs and debuffs each brought by one class.
+var Buffs = []Provider{
+	{"attack-power", "Battle Shout", []string{"warrior"}},
+	{"stamina", "Power Word: Fortitude", []string{"priest"}},
+	{"intellect", "Arcane Intellect", []string{"mage"}},
+	{"versatility", "Mark of the Wild", []string{"druid"}},
+	{"mastery", "Skyfury", []string{"shaman"}},
+	{"movement", "Blessing of the Bronze", []string{"evoker"}},
+	{"physical-damage", "Mystic Touch", []string{"monk"}},
+	{"magic-damage", "Chaos Brand", []string{"demon-hunter"}},
+	{"damage-reduction", "Devotion Aura", []string{"paladin"}},
+	{"hunters-mark", "Hunter's Mark", []string{"hunter"}},
+}
+
+// Utility are the raid cooldowns a raid wants at least one of.
+var Utility = []Provider{
+	{"bloodlust", "Bloodlust", []string{"shaman", "mage", "hunter", "evoker"}},
+	{"battle-res", "Battle resurrection", []string{"druid", "death-knight", "warlock", "paladin"}},
+	{"healthstones", "Healthstones", []string{"warlock"}},
+	{"raid-speed", "Raid movement speed", []string{"druid", "evoker"}},
+}
+
+// Counts are the characters that confirmed or are tentative.
+type Counts struct {
+	Confirmed int `json:"confirmed"`
+	Tentative int `json:"tentative"`
+}
+
+// RoleCounts are the characters of a raid role.
+type RoleCounts struct {
+	Counts
+	// OffSpec counts confirmed characters of another role whose off-spec
+	// fills this one.
+	OffSpec int `json:"off_spec"`
+}
+
+// Coverage is how many characters bring a buff or utility.
+type Coverage struct {
+	Provider
+	Counts
+	// Missing is set when no confirmed character brings it.
+	Missing bool `json:"missing"`
+}
+
+// Composition is the breakdown of an event's confirmed and tentative
+// characters. Roles, Classes and ArmorTypes have an entry for every known
+// value, even when nobody answered with it.
+type Composition struct {
+	Total       Counts                 `json:"total"`
+	Roles       map[string]*RoleCounts `json:"roles"`
+	UnknownRole Counts                 `json:"unknown_role"` // Characters without a preferred role or known spec
+	Classes     map[string]*Counts     `json:"classes"`
+	ArmorTypes  map[string]*Counts     `json:"armor_types"`
+	Buffs       []Coverage             `json:"buffs"`
+	Utility     []Coverage             `json:"utility"`
+	// Missing names the buffs and utilities no confirmed character brings.
+	Missing []string `json:"missing"`
+}
+
+// Build computes the composition of the confirmations' characters. Pending
+// and declined answers, and confirmations of characters not in characters,
+// are ignored.
+func Build(confirmations []models.Confirmation, characters []models.Character) *Composition {
+	comp := &Composition{
+		Roles:      make(map[string]*RoleCounts, len(models.RaidRoles)),
+		Classes:    make(map[string]*Counts, len(models.Classes)),
+		ArmorTypes: make(map[string]*Counts, len(models.ArmorTypes)),
+		Missing:    []string{},
+	}
+	for _, role := range models.RaidRoles {
+		comp.Roles[role] = &RoleCounts{}
+	}
+	for _, class := range models.Classes {
+		comp.Classes[class] = &Counts{}
+	}
+	for _, armor := range models.ArmorTypes {
+		comp.ArmorTypes[armor] = &Counts{}
+	}
+
+	byID := make(map[string]*models.Character, len(characters))
+	for i := range characters {
+		byID[characters[i].ID] = &characters[i]
+	}
+	// Classes brought by confirmed and by tentative characters.
+	confirmedClasses := map[string]int{}
+	tentativeClasses := map[string]int{}
+
+	for _, confirmation := range confirmations {
+		character, ok := byID[confirmation.CharacterID]
+		if !ok {
+			continue
+		}
+		confirmed := confirmation.Status == models.ConfirmationConfirmed
+		if !confirmed && confirmation.Status != models.ConfirmationTentative {
+			continue
+		}
+		count := func(c *Counts) {
+			if confirmed {
+				c.Confirmed++
+			} else {
+				c.Tentative++
+			}
+		}
+
+		count(&comp.Total)
+		if role, ok := comp.Roles[character.Role()]; ok {
+			count(&role.Counts)
+		} else {
+			count(&comp.UnknownRole)
+		}
+		if off, ok := comp.Roles[character.OffRole()]; ok && confirmed {
+			off.OffSpec++
+		}
+		if class, ok := comp.Classes[character.Class]; ok {
+			count(class)
+		}
+		if armor, ok := comp.ArmorTypes[models.ClassArmor[character.Class]]; ok {
+			count(armor)
+		}
+		if confirmed {
+			confirmedClasses[character.Class]++
+		} else {
+			tentativeClasses[character.Class]++
+		}
+	}
+
+	cover := func(providers []Provider) []Coverage {
+		coverage := make([]Coverage, len(providers))
+		for i, p := range providers {
+			coverage[i].Provider = p
+			for _, class := range p.Classes {
+				coverage[i].Confirmed += confirmedClasses[class]
+				coverage[i].Tentative += tentativeClasses[class]
+			}
+			if coverage[i].Confirmed == 0 {
+				coverage[i].Missing = true
+				comp.Missing = append(comp.Missing, p.Name)
+			}
+		}
+		return coverage
+	}
+	comp.Buffs = cover(Buffs)
+	comp.Utility = cover(Utility)
+	return comp
+}
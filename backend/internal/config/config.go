@@ -2,66 +2,286 @@ package config
 
 import (
 	"fmt"
+	"log/slog"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
 )
 
-// Config holds all configuration for the application.
+// Config holds all configuration for the application. Every setting can come
+// from an optional YAML or TOML file and is overridden by its environment
+// variable; see settings for the names.
 type Config struct {
+	// Environment names the deployment, e.g. development or production.
+	Environment string `mapstructure:"environment"`
+	// HTTP server settings
+	Server struct {
+		Addr         string        `mapstructure:"addr"`
+		ReadTimeout  time.Duration `mapstructure:"read_timeout"`
+		WriteTimeout time.Duration `mapstructure:"write_timeout"`
+		IdleTimeout  time.Duration `mapstructure:"idle_timeout"`
+		// ShutdownTimeout is how long in-flight requests and background
+		// workers get to finish after SIGINT or SIGTERM.
+		ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+		// TrustedProxies are the IP addresses or CIDR ranges of the reverse
+		// proxies whose X-Forwarded-For header is believed. When empty, the
+		// client IP is the address of the connection's peer.
+		TrustedProxies []string `mapstructure:"trusted_proxies"`
+		// TrustedPlatform names the CDN or hosting platform whose client IP
+		// header is believed instead, one of TrustedPlatforms.
+		TrustedPlatform string `mapstructure:"trusted_platform"`
+		// PublicURL is the scheme and host users reach the app on, used to
+		// build links handed out for use outside the browser.
+		PublicURL string `mapstructure:"public_url"`
+	} `mapstructure:"server"`
+	// Logging settings
+	Log struct {
+		Level  string `mapstructure:"level"`
+		Format string `mapstructure:"format"`
+	} `mapstructure:"log"`
+	// Prometheus metrics settings
+	Metrics struct {
+		Enabled bool `mapstructure:"enabled"`
+		// Addr serves /metrics on a separate admin listener, e.g. ":9090".
+		// When empty, /metrics is served by the main server.
+		Addr string `mapstructure:"addr"`
+	} `mapstructure:"metrics"`
 	// PostgreSQL settings
-	Database struct {
-		Host     string
-		Port     string
-		User     string
-		Password string
-		DBName   string
-		SSLMode  string
-	}
+	Database DatabaseConfig `mapstructure:"database"`
 	// Redis settings
 	Redis struct {
-		Addr     string
-		Password string
-		DB       int
-		Timeout  time.Duration
-	}
+		Addr     string        `mapstructure:"addr"`
+		Password string        `mapstructure:"password"`
+		DB       int           `mapstructure:"db"`
+		Timeout  time.Duration `mapstructure:"timeout"`
+	} `mapstructure:"redis"`
 	// Rate limiting settings
 	RateLimit struct {
-		RequestsPerMinute int
+		// Algorithm is sliding_window or token_bucket.
+		Algorithm string `mapstructure:"algorithm"`
+		// RequestsPerMinute applies to every route but the probes and
+		// metrics; the stricter limits below apply on top of it.
+		RequestsPerMinute     int `mapstructure:"requests_per_minute"`
+		AuthRequestsPerMinute int `mapstructure:"auth_requests_per_minute"`
+		SyncRequestsPerHour   int `mapstructure:"sync_requests_per_hour"`
+		// FailOpen lets requests through while Redis is unavailable.
+		FailOpen bool `mapstructure:"fail_open"`
+	} `mapstructure:"rate_limit"`
+	// Battle.net OAuth2 settings
+	BattleNet struct {
+		ClientID     string `mapstructure:"client_id"`
+		ClientSecret string `mapstructure:"client_secret"`
+		Region       string `mapstructure:"region"`
+		RedirectURL  string `mapstructure:"redirect_url"`
+		// OAuthURL overrides the region's OAuth host, e.g. to point at a
+		// local fake authorization server during development.
+		OAuthURL string `mapstructure:"oauth_url"`
+	} `mapstructure:"battlenet"`
+	// Character sync settings
+	Sync struct {
+		Enabled bool `mapstructure:"enabled"`
+		// Interval is how old a character's data may get before it is
+		// refreshed; CheckInterval is how often stale characters are looked for.
+		Interval      time.Duration `mapstructure:"interval"`
+		CheckInterval time.Duration `mapstructure:"check_interval"`
+		BatchSize     int           `mapstructure:"batch_size"`
+	} `mapstructure:"sync"`
+	// Recurring event generation settings
+	Schedule struct {
+		Enabled bool `mapstructure:"enabled"`
+		// WeeksAhead is how far ahead events are generated from raid group
+		// schedules; CheckInterval is how often generation runs.
+		WeeksAhead    int           `mapstructure:"weeks_ahead"`
+		CheckInterval time.Duration `mapstructure:"check_interval"`
+	} `mapstructure:"schedule"`
+	// RSVP settings
+	RSVP struct {
+		// Cutoff is how long before an event answers are flagged as late.
+		Cutoff time.Duration `mapstructure:"cutoff"`
+	} `mapstructure:"rsvp"`
+	// Session settings
+	Session struct {
+		Secret string        `mapstructure:"secret"`
+		TTL    time.Duration `mapstructure:"ttl"`
+		Secure bool          `mapstructure:"secure"`
+	} `mapstructure:"session"`
+	// CSRF protection settings
+	CSRF struct {
+		Key string `mapstructure:"key"`
+	} `mapstructure:"csrf"`
+}
+
+// DatabaseConfig holds the PostgreSQL connection settings.
+type DatabaseConfig struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	User     string `mapstructure:"user"`
+	Password string `mapstructure:"password"`
+	DBName   string `mapstructure:"dbname"`
+	SSLMode  string `mapstructure:"sslmode"`
+	// ConnectRetries is how many times the server tries to reach the
+	// database on startup.
+	ConnectRetries int `mapstructure:"connect_retries"`
+}
+
+// LogValue keeps the password out of logs.
+func (d DatabaseConfig) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("host", d.Host),
+		slog.Int("port", d.Port),
+		slog.String("user", d.User),
+		slog.String("dbname", d.DBName),
+		slog.String("sslmode", d.SSLMode),
+	)
+}
+
+// DSN returns the connection string for the PostgreSQL driver.
+func (d DatabaseConfig) DSN() string {
+	return fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		d.Host, d.Port, d.User, d.Password, d.DBName, d.SSLMode,
+	)
+}
+
+// setting binds a config key to its environment variable. A nil default
+// leaves the key unset, so Validate can report it as missing.
+type setting struct {
+	key string
+	env string
+	def interface{}
+}
+
+// settings lists every config key. Keep it in sync with .env.example.
+var settings = []setting{
+	{"environment", "APP_ENV", "development"},
+
+	{"server.addr", "SERVER_ADDRESS", ":8080"},
+	{"server.read_timeout", "SERVER_READ_TIMEOUT", 15 * time.Second},
+	{"server.write_timeout", "SERVER_WRITE_TIMEOUT", time.Minute},
+	{"server.idle_timeout", "SERVER_IDLE_TIMEOUT", 2 * time.Minute},
+	{"server.shutdown_timeout", "SERVER_SHUTDOWN_TIMEOUT", 20 * time.Second},
+	{"server.trusted_proxies", "SERVER_TRUSTED_PROXIES", []string{}},
+	{"server.trusted_platform", "SERVER_TRUSTED_PLATFORM", ""},
+	{"server.public_url", "PUBLIC_URL", "http://localhost"},
+
+	{"log.level", "LOG_LEVEL", "info"},
+	{"log.format", "LOG_FORMAT", "json"},
+
+	{"metrics.enabled", "METRICS_ENABLED", true},
+	{"metrics.addr", "METRICS_ADDRESS", ""},
+
+	{"database.host", "POSTGRES_HOST", "postgres"},
+	{"database.port", "POSTGRES_PORT", 5432},
+	{"database.user", "POSTGRES_USER", nil},
+	{"database.password", "POSTGRES_PASSWORD", nil},
+	{"database.dbname", "POSTGRES_DB", "guild_manager"},
+	{"database.sslmode", "POSTGRES_SSL_MODE", "disable"},
+	{"database.connect_retries", "MIGRATION_MAX_RETRIES", 10},
+
+	{"redis.addr", "REDIS_ADDRESS", "redis:6379"},
+	{"redis.password", "REDIS_PASSWORD", ""},
+	{"redis.db", "REDIS_DB", 0},
+	{"redis.timeout", "REDIS_TIMEOUT", 5 * time.Second},
+
+	{"rate_limit.algorithm", "RATE_LIMIT_ALGORITHM", "sliding_window"},
+	{"rate_limit.requests_per_minute", "RATE_LIMIT_REQUESTS_PER_MINUTE", 60},
+	{"rate_limit.auth_requests_per_minute", "RATE_LIMIT_AUTH_REQUESTS_PER_MINUTE", 10},
+	{"rate_limit.sync_requests_per_hour", "RATE_LIMIT_SYNC_REQUESTS_PER_HOUR", 10},
+	{"rate_limit.fail_open", "RATE_LIMIT_FAIL_OPEN", true},
+
+	{"battlenet.client_id", "BNET_CLIENT_ID", ""},
+	{"battlenet.client_secret", "BNET_CLIENT_SECRET", ""},
+	{"battlenet.region", "BNET_REGION", "eu"},
+	{"battlenet.redirect_url", "BNET_REDIRECT_URL", "http://localhost/auth/callback"},
+	{"battlenet.oauth_url", "BNET_OAUTH_URL", ""},
+
+	{"sync.enabled", "SYNC_ENABLED", true},
+	{"sync.interval", "SYNC_INTERVAL", 24 * time.Hour},
+	{"sync.check_interval", "SYNC_CHECK_INTERVAL", time.Hour},
+	{"sync.batch_size", "SYNC_BATCH_SIZE", 50},
+
+	{"schedule.enabled", "SCHEDULE_ENABLED", true},
+	{"schedule.weeks_ahead", "SCHEDULE_WEEKS_AHEAD", 4},
+	{"schedule.check_interval", "SCHEDULE_CHECK_INTERVAL", time.Hour},
+
+	{"rsvp.cutoff", "RSVP_CUTOFF", 2 * time.Hour},
+
+	{"session.secret", "SESSION_SECRET", nil},
+	{"session.ttl", "SESSION_TTL", 7 * 24 * time.Hour},
+	{"session.secure", "SESSION_SECURE", false},
+
+	{"csrf.key", "CSRF_KEY", nil},
+}
+
+// envName returns the environment variable of a config key.
+func envName(key string) string {
+	for _, s := range settings {
+		if s.key == key {
+			return s.env
+		}
 	}
+	return strings.ToUpper(strings.NewReplacer(".", "_").Replace(key))
 }
 
-// LoadConfig loads configuration using Viper.
+// LoadConfig loads the configuration and validates all of it.
 func LoadConfig() (*Config, error) {
-	// Set environment variable prefix if you like (optional)
-	// viper.SetEnvPrefix("APP")
-
-	viper.AutomaticEnv()
+	cfg, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
 
-	// Set defaults for Database
-	viper.SetDefault("database.host", "postgres")
-	viper.SetDefault("database.port", "5432")
-	viper.SetDefault("database.user", "admin")
-	viper.SetDefault("database.password", "secret")
-	viper.SetDefault("database.dbname", "guild_manager")
-	viper.SetDefault("database.sslmode", "disable")
+// Load reads the configuration from the file named by CONFIG_FILE, if any,
+// and the environment, without validating it. Tools that only need part of
+// the settings validate that part themselves.
+func Load() (*Config, error) {
+	return LoadFile(os.Getenv("CONFIG_FILE"))
+}
 
-	// Set defaults for Redis
-	viper.SetDefault("redis.addr", "redis:6379")
-	viper.SetDefault("redis.password", "")
-	viper.SetDefault("redis.db", 0)
-	viper.SetDefault("redis.timeout", 5) // seconds
+// LoadFile reads the configuration without validating it. file may be empty,
+// or a YAML or TOML file whose keys mirror the config struct, e.g.
+//
+//	database:
+//	  host: localhost
+//
+// Environment variables take precedence over the file.
+func LoadFile(file string) (*Config, error) {
+	v := viper.New()
+	for _, s := range settings {
+		if s.def != nil {
+			v.SetDefault(s.key, s.def)
+		}
+		if err := v.BindEnv(s.key, s.env); err != nil {
+			return nil, err
+		}
+	}
 
-	// Set defaults for Rate Limiting
-	viper.SetDefault("ratelimit.requestspersminute", 60)
+	if file != "" {
+		v.SetConfigFile(file)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("failed to read config file %s: %w", file, err)
+		}
+	}
 
 	var cfg Config
-	if err := viper.Unmarshal(&cfg); err != nil {
+	if err := v.Unmarshal(&cfg); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal configuration: %w", err)
 	}
-
-	// Convert timeout to time.Duration (if needed)
-	cfg.Redis.Timeout = cfg.Redis.Timeout * time.Second
-
+	// Lists set in the environment are comma-separated, maybe with spaces.
+	for i, proxy := range cfg.Server.TrustedProxies {
+		cfg.Server.TrustedProxies[i] = strings.TrimSpace(proxy)
+	}
 	return &cfg, nil
 }
+
+// IsProduction reports whether the config marks the environment as
+// production, where destructive maintenance commands refuse to run.
+func (c *Config) IsProduction() bool {
+	return strings.EqualFold(c.Environment, "production")
+}
@@ -0,0 +1,174 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/logging"
+	"github.com/GFerreiroS/guild-manager/backend/internal/middleware"
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+)
+
+// Environments lists the accepted values of APP_ENV.
+var Environments = []string{"development", "test", "staging", "production"}
+
+// TrustedPlatforms maps the accepted values of SERVER_TRUSTED_PLATFORM to the
+// header that carries the client IP on that platform.
+var TrustedPlatforms = map[string]string{
+	"cloudflare":        gin.PlatformCloudflare,
+	"google_app_engine": gin.PlatformGoogleAppEngine,
+	"flyio":             gin.PlatformFlyIO,
+}
+
+// sslModes lists the sslmode values libpq understands.
+var sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// ValidationError lists every invalid setting, named by its environment
+// variable.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// problems collects validation failures.
+type problems []string
+
+func (p *problems) add(key, message string) {
+	*p = append(*p, envName(key)+" "+message)
+}
+
+func (p *problems) required(key, value string) {
+	if strings.TrimSpace(value) == "" {
+		p.add(key, "is required")
+	}
+}
+
+func (p *problems) positive(key string, value int) {
+	if value < 1 {
+		p.add(key, "must be positive")
+	}
+}
+
+func (p *problems) positiveDuration(key string, value time.Duration) {
+	if value <= 0 {
+		p.add(key, "must be a positive duration such as 30s or 1h")
+	}
+}
+
+func (p *problems) oneOf(key, value string, allowed []string) {
+	if !slices.Contains(allowed, value) {
+		p.add(key, "must be one of: "+strings.Join(allowed, ", "))
+	}
+}
+
+func (p *problems) absoluteURL(key, value string) {
+	if u, err := url.Parse(value); err != nil || u.Scheme == "" || u.Host == "" {
+		p.add(key, "must be an absolute URL")
+	}
+}
+
+func (p problems) err() error {
+	if len(p) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: p}
+}
+
+// Validate checks every setting the server needs and returns a
+// *ValidationError listing all problems at once.
+func (c *Config) Validate() error {
+	var p problems
+	p.oneOf("environment", strings.ToLower(c.Environment), Environments)
+	p.required("server.addr", c.Server.Addr)
+	p.positiveDuration("server.read_timeout", c.Server.ReadTimeout)
+	p.positiveDuration("server.write_timeout", c.Server.WriteTimeout)
+	p.positiveDuration("server.idle_timeout", c.Server.IdleTimeout)
+	p.positiveDuration("server.shutdown_timeout", c.Server.ShutdownTimeout)
+	for _, proxy := range c.Server.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				p.add("server.trusted_proxies", fmt.Sprintf("must list IP addresses or CIDR ranges, not %q", proxy))
+			}
+		}
+	}
+	p.absoluteURL("server.public_url", c.Server.PublicURL)
+	if c.Server.TrustedPlatform != "" {
+		p.oneOf("server.trusted_platform", c.Server.TrustedPlatform, slices.Sorted(maps.Keys(TrustedPlatforms)))
+	}
+	p.oneOf("log.level", strings.ToLower(c.Log.Level), logging.Levels)
+	p.oneOf("log.format", strings.ToLower(c.Log.Format), logging.Formats)
+	if c.Metrics.Enabled && c.Metrics.Addr != "" && c.Metrics.Addr == c.Server.Addr {
+		p.add("metrics.addr", "must differ from "+envName("server.addr"))
+	}
+	c.Database.check(&p)
+
+	p.required("redis.addr", c.Redis.Addr)
+	if c.Redis.DB < 0 {
+		p.add("redis.db", "must not be negative")
+	}
+	p.positiveDuration("redis.timeout", c.Redis.Timeout)
+
+	p.oneOf("rate_limit.algorithm", c.RateLimit.Algorithm, middleware.RateLimitAlgorithms)
+	p.positive("rate_limit.requests_per_minute", c.RateLimit.RequestsPerMinute)
+	p.positive("rate_limit.auth_requests_per_minute", c.RateLimit.AuthRequestsPerMinute)
+	p.positive("rate_limit.sync_requests_per_hour", c.RateLimit.SyncRequestsPerHour)
+
+	p.oneOf("battlenet.region", c.BattleNet.Region, models.Regions)
+	p.absoluteURL("battlenet.redirect_url", c.BattleNet.RedirectURL)
+	if c.BattleNet.OAuthURL != "" {
+		p.absoluteURL("battlenet.oauth_url", c.BattleNet.OAuthURL)
+	}
+	if (c.BattleNet.ClientID == "") != (c.BattleNet.ClientSecret == "") {
+		p.add("battlenet.client_secret", "and "+envName("battlenet.client_id")+" must be set together")
+	}
+
+	if c.Sync.Enabled {
+		p.positiveDuration("sync.interval", c.Sync.Interval)
+		p.positiveDuration("sync.check_interval", c.Sync.CheckInterval)
+		p.positive("sync.batch_size", c.Sync.BatchSize)
+	}
+	if c.Schedule.Enabled {
+		if c.Schedule.WeeksAhead < 1 || c.Schedule.WeeksAhead > 52 {
+			p.add("schedule.weeks_ahead", "must be between 1 and 52")
+		}
+		p.positiveDuration("schedule.check_interval", c.Schedule.CheckInterval)
+	}
+	if c.RSVP.Cutoff < 0 {
+		p.add("rsvp.cutoff", "must not be negative")
+	}
+
+	p.required("session.secret", c.Session.Secret)
+	p.positiveDuration("session.ttl", c.Session.TTL)
+	p.required("csrf.key", c.CSRF.Key)
+	return p.err()
+}
+
+// Validate checks the connection settings only, for tools that just need
+// the database.
+func (d DatabaseConfig) Validate() error {
+	var p problems
+	d.check(&p)
+	return p.err()
+}
+
+func (d DatabaseConfig) check(p *problems) {
+	p.required("database.host", d.Host)
+	if d.Port < 1 || d.Port > 65535 {
+		p.add("database.port", "must be between 1 and 65535")
+	}
+	p.required("database.user", d.User)
+	p.required("database.password", d.Password)
+	p.required("database.dbname", d.DBName)
+	p.oneOf("database.sslmode", d.SSLMode, sslModes)
+	p.positive("database.connect_retries", d.ConnectRetries)
+}
@@ -1,74 +1,382 @@
 package database
 
 import (
+	"context"
+	"database/sql"
 	"embed"
+	"errors"
 	"fmt"
-	"log"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
 	"github.com/golang-migrate/migrate/v4/source/iofs"
 	"gorm.io/gorm"
 )
 
 //go:embed migrations/*.sql
-var fs embed.FS
+var migrationFS embed.FS
+
+// MigrationFile is a numbered pair of up and down SQL files.
+type MigrationFile struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStep is a single file a migration run would execute.
+type MigrationStep struct {
+	File string
+	SQL  string
+}
+
+// MigrationStatus describes the database's migration state. Version is 0 and
+// Applied false when no migration ran yet.
+type MigrationStatus struct {
+	Version uint
+	Applied bool
+	Dirty   bool
+	// Unknown is set when no embedded migration has Version, e.g. when the
+	// database was migrated by a newer binary. Pending is empty then.
+	Unknown bool
+	Pending []MigrationFile
+}
+
+// Migrator runs the embedded migrations against a database.
+type Migrator struct {
+	m     *migrate.Migrate
+	files []MigrationFile
+}
+
+// NewMigrator validates the embedded migrations and builds a migrator for db.
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	files, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
 
-// RunMigrations applies all pending migrations>
-func RunMigrations(db *gorm.DB) error {
 	sqlDB, err := db.DB()
 	if err != nil {
-		return fmt.Errorf("failed to get generic DB: %w", err)
+		return nil, fmt.Errorf("failed to get generic DB: %w", err)
 	}
 
 	driver, err := postgres.WithInstance(sqlDB, &postgres.Config{})
 	if err != nil {
-		return fmt.Errorf("failed to create driver: %w", err)
+		return nil, fmt.Errorf("failed to create driver: %w", err)
 	}
 
-	source, err := iofs.New(fs, "migrations")
+	src, err := iofs.New(migrationFS, "migrations")
 	if err != nil {
-		return fmt.Errorf("failed to create source: %w", err)
+		return nil, fmt.Errorf("failed to create source: %w", err)
 	}
 
-	m, err := migrate.NewWithInstance("iofs", source, "postgres", driver)
+	m, err := migrate.NewWithInstance("iofs", src, "postgres", driver)
 	if err != nil {
-		return fmt.Errorf("failed to create migrator: %w", err)
+		return nil, fmt.Errorf("failed to create migrator: %w", err)
 	}
+	return &Migrator{m: m, files: files}, nil
+}
 
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("migration failed: %w", err)
+// RunMigrations applies all pending migrations.
+func RunMigrations(db *gorm.DB) error {
+	m, err := NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	if err := m.Up(); err != nil {
+		return err
 	}
 
-	log.Println("✅ Database migrations applied successfully")
+	slog.Info("database migrations applied")
 	return nil
 }
 
 // ForceVersion forces the database to a specific migration version
 func ForceVersion(db *gorm.DB, version int) error {
-	sqlDB, err := db.DB()
+	m, err := NewMigrator(db)
 	if err != nil {
-		return fmt.Errorf("failed to get generic DB: %w", err)
+		return err
 	}
+	return m.Force(version)
+}
 
-	driver, err := postgres.WithInstance(sqlDB, &postgres.Config{})
+// Up applies all pending migrations.
+func (m *Migrator) Up() error {
+	return noChange(m.m.Up(), "migration failed")
+}
+
+// Down reverts the last n migrations, or all of them when n is 0.
+func (m *Migrator) Down(n int) error {
+	if n == 0 {
+		return noChange(m.m.Down(), "migration failed")
+	}
+	return noChange(m.m.Steps(-n), "migration failed")
+}
+
+// Goto migrates up or down to version.
+func (m *Migrator) Goto(version uint) error {
+	return noChange(m.m.Migrate(version), "migration failed")
+}
+
+// Steps applies n migrations, or reverts -n when n is negative.
+func (m *Migrator) Steps(n int) error {
+	return noChange(m.m.Steps(n), "migration failed")
+}
+
+// Force sets the version without running anything and clears the dirty flag.
+func (m *Migrator) Force(version int) error {
+	if err := m.m.Force(version); err != nil {
+		return fmt.Errorf("force version failed: %w", err)
+	}
+	return nil
+}
+
+// Status reports the current version and the migrations still to apply.
+func (m *Migrator) Status() (*MigrationStatus, error) {
+	version, dirty, err := m.m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return nil, fmt.Errorf("failed to read version: %w", err)
+	}
+	status := &MigrationStatus{Version: version, Applied: err == nil, Dirty: dirty}
+	if !status.Applied {
+		status.Pending = m.files
+		return status, nil
+	}
+
+	current := slices.IndexFunc(m.files, func(f MigrationFile) bool { return f.Version == version })
+	if current < 0 {
+		status.Unknown = true
+		return status, nil
+	}
+	status.Pending = m.files[current+1:]
+	return status, nil
+}
+
+// SchemaStatus reads the applied version straight from the schema_migrations
+// table. Unlike Migrator.Status it neither holds a connection of its own nor
+// fails on versions it has no file for, so health checks can call it often.
+func SchemaStatus(ctx context.Context, db *sql.DB) (*MigrationStatus, error) {
+	files, err := Migrations()
 	if err != nil {
-		return fmt.Errorf("failed to create driver: %w", err)
+		return nil, err
+	}
+
+	status := &MigrationStatus{}
+	var version int64
+	err = db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &status.Dirty)
+	switch {
+	case err == nil:
+		status.Version, status.Applied = uint(version), true
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, fmt.Errorf("failed to read version: %w", err)
 	}
+	for _, f := range files {
+		if !status.Applied || f.Version > status.Version {
+			status.Pending = append(status.Pending, f)
+		}
+	}
+	return status, nil
+}
+
+// LatestVersion is the version of the newest embedded migration, or 0.
+func LatestVersion() (uint, error) {
+	files, err := Migrations()
+	if err != nil || len(files) == 0 {
+		return 0, err
+	}
+	return files[len(files)-1].Version, nil
+}
 
-	source, err := iofs.New(fs, "migrations")
+// PlanUp lists the files Up would execute.
+func (m *Migrator) PlanUp() ([]MigrationStep, error) {
+	return m.plan(func(int) (int, error) { return len(m.files) - 1, nil })
+}
+
+// PlanDown lists the files Down(n) would execute.
+func (m *Migrator) PlanDown(n int) ([]MigrationStep, error) {
+	if n == 0 {
+		return m.plan(func(int) (int, error) { return -1, nil })
+	}
+	return m.PlanSteps(-n)
+}
+
+// PlanGoto lists the files Goto(version) would execute.
+func (m *Migrator) PlanGoto(version uint) ([]MigrationStep, error) {
+	return m.plan(func(int) (int, error) {
+		for i, f := range m.files {
+			if f.Version == version {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("no migration with version %d", version)
+	})
+}
+
+// PlanSteps lists the files Steps(n) would execute.
+func (m *Migrator) PlanSteps(n int) ([]MigrationStep, error) {
+	return m.plan(func(current int) (int, error) {
+		target := current + n
+		if target < -1 || target >= len(m.files) {
+			return 0, fmt.Errorf("cannot move %d steps from the current version", n)
+		}
+		return target, nil
+	})
+}
+
+// plan lists the files between the current version and the index returned
+// by target, where -1 means no migration applied.
+func (m *Migrator) plan(target func(current int) (int, error)) ([]MigrationStep, error) {
+	current, err := m.current()
 	if err != nil {
-		return fmt.Errorf("failed to create source: %w", err)
+		return nil, err
+	}
+	to, err := target(current)
+	if err != nil {
+		return nil, err
 	}
 
-	m, err := migrate.NewWithInstance("iofs", source, "postgres", driver)
+	var steps []MigrationStep
+	for i := current + 1; i <= to; i++ {
+		step, err := readStep(m.files[i].Up)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+	for i := current; i > to; i-- {
+		step, err := readStep(m.files[i].Down)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// current returns the index of the applied version in m.files, or -1.
+func (m *Migrator) current() (int, error) {
+	version, _, err := m.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return -1, nil
+	}
 	if err != nil {
-		return fmt.Errorf("failed to create migrator: %w", err)
+		return 0, fmt.Errorf("failed to read version: %w", err)
+	}
+	for i, f := range m.files {
+		if f.Version == version {
+			return i, nil
+		}
 	}
+	return 0, fmt.Errorf("database is at version %d, which has no migration file", version)
+}
 
-	if err := m.Force(version); err != nil {
-		return fmt.Errorf("force version failed: %w", err)
+// Migrations lists the embedded migrations in version order, failing if an
+// up file has no matching down file or the other way around.
+func Migrations() ([]MigrationFile, error) {
+	return readMigrations(migrationFS, "migrations")
+}
+
+// CreateMigration scaffolds the next numbered up/down pair in dir and returns
+// their paths.
+func CreateMigration(dir, name string) (string, string, error) {
+	slug := strings.Trim(regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if slug == "" {
+		return "", "", fmt.Errorf("migration name %q has no usable characters", name)
 	}
 
-	return nil
+	files, err := readMigrations(os.DirFS(dir), ".")
+	if err != nil {
+		return "", "", err
+	}
+	var next uint = 1
+	if len(files) > 0 {
+		next = files[len(files)-1].Version + 1
+	}
+
+	base := filepath.Join(dir, fmt.Sprintf("%04d_%s", next, slug))
+	up, down := base+".up.sql", base+".down.sql"
+	for _, path := range []string{up, down} {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to create %s: %w", path, err)
+		}
+		if err := f.Close(); err != nil {
+			return "", "", err
+		}
+	}
+	return up, down, nil
+}
+
+func readMigrations(fsys fs.FS, dir string) ([]MigrationFile, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	byVersion := map[uint]*MigrationFile{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+		parsed, err := source.DefaultParse(entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration file name %s: %w", entry.Name(), err)
+		}
+
+		f, ok := byVersion[parsed.Version]
+		if !ok {
+			f = &MigrationFile{Version: parsed.Version, Name: parsed.Identifier}
+			byVersion[parsed.Version] = f
+		}
+		path := filepath.ToSlash(filepath.Join(dir, entry.Name()))
+		if parsed.Direction == source.Up {
+			f.Up = path
+		} else {
+			f.Down = path
+		}
+	}
+
+	files := make([]MigrationFile, 0, len(byVersion))
+	var missing []string
+	for _, f := range byVersion {
+		if f.Up == "" {
+			missing = append(missing, fmt.Sprintf("%04d_%s.up.sql", f.Version, f.Name))
+		}
+		if f.Down == "" {
+			missing = append(missing, fmt.Sprintf("%04d_%s.down.sql", f.Version, f.Name))
+		}
+		files = append(files, *f)
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("migrations are missing their counterpart: %s", strings.Join(missing, ", "))
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Version < files[j].Version })
+	return files, nil
+}
+
+func readStep(path string) (MigrationStep, error) {
+	sql, err := migrationFS.ReadFile(path)
+	if err != nil {
+		return MigrationStep{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return MigrationStep{File: filepath.Base(path), SQL: string(sql)}, nil
+}
+
+// noChange treats migrate.ErrNoChange as success and wraps other errors.
+func noChange(err error, msg string) error {
+	if err == nil || errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+	return fmt.Errorf("%s: %w", msg, err)
 }
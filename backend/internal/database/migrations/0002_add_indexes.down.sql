@@ -0,0 +1,8 @@
+-- idx_characters_guild is left alone: 0001 already creates it.
+DROP INDEX IF EXISTS idx_events_scheduled;
+DROP INDEX IF EXISTS idx_raid_group_members_group;
+DROP INDEX IF EXISTS idx_raid_group_members_char;
+DROP INDEX IF EXISTS idx_guild_members_guild;
+DROP INDEX IF EXISTS idx_guild_members_user;
+DROP INDEX IF EXISTS idx_confirmations_event;
+DROP INDEX IF EXISTS idx_events_guild;
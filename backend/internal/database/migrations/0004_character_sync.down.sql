@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_characters_last_synced;
+
+ALTER TABLE characters DROP COLUMN IF EXISTS sync_error;
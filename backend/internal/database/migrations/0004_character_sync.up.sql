@@ -0,0 +1,3 @@
+ALTER TABLE characters ADD COLUMN IF NOT EXISTS sync_error TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_characters_last_synced ON characters(last_synced NULLS FIRST);
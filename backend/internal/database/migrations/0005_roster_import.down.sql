@@ -0,0 +1,6 @@
+ALTER TABLE guilds DROP COLUMN IF EXISTS roster_synced_at;
+
+ALTER TABLE characters
+    DROP COLUMN IF EXISTS left_guild_at,
+    DROP COLUMN IF EXISTS guild_rank,
+    DROP COLUMN IF EXISTS level;
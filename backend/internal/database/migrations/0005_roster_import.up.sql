@@ -0,0 +1,9 @@
+-- Characters imported from the in-game roster are not linked to a user yet.
+ALTER TABLE characters ALTER COLUMN user_id DROP NOT NULL;
+
+ALTER TABLE characters
+    ADD COLUMN IF NOT EXISTS level INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS guild_rank INTEGER,
+    ADD COLUMN IF NOT EXISTS left_guild_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE guilds ADD COLUMN IF NOT EXISTS roster_synced_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,17 @@
+DROP INDEX IF EXISTS idx_events_raid_group;
+ALTER TABLE events DROP COLUMN IF EXISTS raid_group_id;
+
+ALTER TABLE confirmations
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS late,
+    ALTER COLUMN responded_at SET DEFAULT CURRENT_TIMESTAMP;
+
+-- Pending confirmations cannot be represented by the old constraint.
+DELETE FROM confirmations WHERE status = 'pending';
+ALTER TABLE confirmations DROP CONSTRAINT IF EXISTS confirmations_status_check;
+ALTER TABLE confirmations
+    ALTER COLUMN status DROP NOT NULL,
+    ALTER COLUMN status DROP DEFAULT,
+    ADD CONSTRAINT confirmations_status_check CHECK (status IN ('confirmed', 'declined', 'tentative'));
+
+DROP INDEX IF EXISTS idx_confirmations_event_character;
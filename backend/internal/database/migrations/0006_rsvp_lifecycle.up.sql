@@ -0,0 +1,35 @@
+-- Keep a single confirmation per event and character, preferring the most
+-- recent answer, before enforcing uniqueness. Rows without responded_at rank
+-- last, and id breaks ties.
+DELETE FROM confirmations
+WHERE id IN (
+    SELECT id FROM (
+        SELECT id, ROW_NUMBER() OVER (
+            PARTITION BY event_id, character_id
+            ORDER BY responded_at DESC NULLS LAST, id DESC
+        ) AS rank
+        FROM confirmations
+        WHERE event_id IS NOT NULL AND character_id IS NOT NULL
+    ) ranked
+    WHERE rank > 1
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_confirmations_event_character ON confirmations(event_id, character_id);
+
+-- Allow the 'pending' state that confirmations start in.
+ALTER TABLE confirmations DROP CONSTRAINT IF EXISTS confirmations_status_check;
+UPDATE confirmations SET status = 'pending' WHERE status IS NULL;
+ALTER TABLE confirmations
+    ALTER COLUMN status SET DEFAULT 'pending',
+    ALTER COLUMN status SET NOT NULL,
+    ADD CONSTRAINT confirmations_status_check CHECK (status IN ('pending', 'confirmed', 'declined', 'tentative'));
+
+-- responded_at is only set when a member actually answers.
+ALTER TABLE confirmations
+    ALTER COLUMN responded_at DROP DEFAULT,
+    ADD COLUMN IF NOT EXISTS late BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP;
+
+-- Events can target a raid group whose characters are asked to respond.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS raid_group_id UUID REFERENCES raid_groups(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_events_raid_group ON events(raid_group_id);
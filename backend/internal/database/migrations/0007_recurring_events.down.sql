@@ -0,0 +1,11 @@
+UPDATE raid_groups
+SET schedule = jsonb_build_object(
+    'days', (SELECT COALESCE(jsonb_agg(initcap(day)), '[]'::jsonb) FROM jsonb_array_elements_text(schedule->'weekdays') AS day),
+    'time', schedule->>'start_time'
+)
+WHERE jsonb_typeof(schedule) = 'object' AND schedule ? 'weekdays';
+
+DROP INDEX IF EXISTS idx_events_raid_group_occurrence;
+ALTER TABLE events
+    DROP COLUMN IF EXISTS ends_at,
+    DROP COLUMN IF EXISTS occurrence_date;
@@ -0,0 +1,17 @@
+-- Events generated from a raid group schedule remember which occurrence they
+-- materialize, so regenerating never duplicates them even after edits.
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS occurrence_date DATE,
+    ADD COLUMN IF NOT EXISTS ends_at TIMESTAMP WITH TIME ZONE;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_events_raid_group_occurrence ON events(raid_group_id, occurrence_date);
+
+-- Convert the legacy {"days": [...], "time": "HH:MM"} schedules to the typed
+-- format, assuming UTC and three hour raids.
+UPDATE raid_groups
+SET schedule = jsonb_build_object(
+    'weekdays', (SELECT COALESCE(jsonb_agg(lower(day)), '[]'::jsonb) FROM jsonb_array_elements_text(schedule->'days') AS day),
+    'start_time', COALESCE(schedule->>'time', '20:00'),
+    'duration_minutes', 180,
+    'timezone', 'UTC'
+)
+WHERE jsonb_typeof(schedule) = 'object' AND schedule ? 'days';
@@ -0,0 +1,6 @@
+ALTER TABLE events
+    DROP COLUMN IF EXISTS sequence,
+    DROP COLUMN IF EXISTS updated_at;
+
+DROP INDEX IF EXISTS idx_users_feed_token;
+ALTER TABLE users DROP COLUMN IF EXISTS feed_token;
@@ -0,0 +1,9 @@
+-- Secret token of each user's calendar feed URLs, rotated on demand.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS feed_token VARCHAR(64);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_feed_token ON users(feed_token);
+
+-- Calendar clients need to know when and how often an event changed.
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS sequence INTEGER NOT NULL DEFAULT 0;
+UPDATE events SET updated_at = created_at WHERE created_at IS NOT NULL;
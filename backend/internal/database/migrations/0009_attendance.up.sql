@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS attendances (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    character_id UUID NOT NULL REFERENCES characters(id) ON DELETE CASCADE,
+    status VARCHAR(50) NOT NULL CHECK (status IN ('present', 'late', 'absent', 'benched', 'excused')),
+    note TEXT,
+    recorded_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_attendances_event_character ON attendances(event_id, character_id);
+CREATE INDEX IF NOT EXISTS idx_attendances_character_id ON attendances(character_id);
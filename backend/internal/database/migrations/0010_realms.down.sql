@@ -0,0 +1,16 @@
+-- Restoring the 0003 constraints fails if several guilds now share a realm
+-- or characters are on realms without a guild; resolve those rows first.
+DROP INDEX IF EXISTS idx_characters_realm_name;
+ALTER TABLE characters ADD CONSTRAINT characters_name_realm_key UNIQUE (name, realm);
+ALTER TABLE characters DROP COLUMN IF EXISTS realm_id;
+
+DROP INDEX IF EXISTS idx_guilds_realm_name;
+ALTER TABLE guilds DROP COLUMN IF EXISTS realm_id;
+
+DROP TABLE IF EXISTS realms;
+
+ALTER TABLE guilds ADD CONSTRAINT unique_guild_realm UNIQUE (realm);
+ALTER TABLE characters
+ADD CONSTRAINT fk_characters_realm
+FOREIGN KEY (realm) REFERENCES guilds(realm)
+ON DELETE CASCADE;
@@ -0,0 +1,46 @@
+-- Realms replace the realm consistency constraints of 0003, which allowed a
+-- single guild per realm and tied characters to guild rows.
+CREATE TABLE IF NOT EXISTS realms (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    region VARCHAR(10) NOT NULL,
+    slug VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    connected_realm_id BIGINT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_realms_region_slug ON realms(region, slug);
+CREATE INDEX IF NOT EXISTS idx_realms_connected_realm_id ON realms(connected_realm_id);
+
+ALTER TABLE characters DROP CONSTRAINT IF EXISTS fk_characters_realm;
+ALTER TABLE guilds DROP CONSTRAINT IF EXISTS unique_guild_realm;
+
+-- Existing rows carry no region; they are assumed to be on the default 'eu'
+-- region. Slugs follow blizzard.Slug: lower case, apostrophes removed and
+-- whitespace runs replaced by dashes.
+INSERT INTO realms (region, slug, name)
+SELECT 'eu', slug, MIN(realm)
+FROM (
+    SELECT realm, array_to_string(regexp_split_to_array(lower(replace(trim(realm), '''', '')), '\s+'), '-') AS slug
+    FROM (SELECT realm FROM guilds UNION SELECT realm FROM characters) AS names
+) AS slugs
+GROUP BY slug
+ON CONFLICT (region, slug) DO NOTHING;
+
+ALTER TABLE guilds ADD COLUMN IF NOT EXISTS realm_id UUID REFERENCES realms(id);
+UPDATE guilds g SET realm_id = r.id
+FROM realms r
+WHERE r.region = 'eu'
+  AND r.slug = array_to_string(regexp_split_to_array(lower(replace(trim(g.realm), '''', '')), '\s+'), '-');
+ALTER TABLE guilds ALTER COLUMN realm_id SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_guilds_realm_name ON guilds(realm_id, name);
+
+ALTER TABLE characters ADD COLUMN IF NOT EXISTS realm_id UUID REFERENCES realms(id);
+UPDATE characters c SET realm_id = r.id
+FROM realms r
+WHERE r.region = 'eu'
+  AND r.slug = array_to_string(regexp_split_to_array(lower(replace(trim(c.realm), '''', '')), '\s+'), '-');
+ALTER TABLE characters ALTER COLUMN realm_id SET NOT NULL;
+
+-- A character is unique per realm, and realms are unique per region.
+ALTER TABLE characters DROP CONSTRAINT IF EXISTS characters_name_realm_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_characters_realm_name ON characters(realm_id, name);
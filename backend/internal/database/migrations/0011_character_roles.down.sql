@@ -0,0 +1,3 @@
+ALTER TABLE characters
+    DROP COLUMN IF EXISTS off_spec,
+    DROP COLUMN IF EXISTS preferred_role;
@@ -0,0 +1,5 @@
+-- The raid role a character signs up as, overriding the role of its current
+-- spec, and the spec it can switch to.
+ALTER TABLE characters
+    ADD COLUMN IF NOT EXISTS preferred_role VARCHAR(20) CHECK (preferred_role IN ('tank', 'healer', 'dps')),
+    ADD COLUMN IF NOT EXISTS off_spec VARCHAR(50);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS lineup_changes;
+DROP TABLE IF EXISTS lineup_slots;
+DROP TABLE IF EXISTS lineups;
@@ -0,0 +1,46 @@
+-- Characters officers picked for an event. version is bumped on every save.
+CREATE TABLE IF NOT EXISTS lineups (
+    event_id UUID PRIMARY KEY REFERENCES events(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL DEFAULT 1,
+    locked_at TIMESTAMP WITH TIME ZONE,
+    locked_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS lineup_slots (
+    event_id UUID NOT NULL REFERENCES lineups(event_id) ON DELETE CASCADE,
+    character_id UUID NOT NULL REFERENCES characters(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('selected', 'standby', 'bench')),
+    PRIMARY KEY (event_id, character_id)
+);
+
+-- Audit trail of lineups. Rows outlive the character they name.
+CREATE TABLE IF NOT EXISTS lineup_changes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('update', 'lock')),
+    character_id UUID REFERENCES characters(id) ON DELETE SET NULL,
+    character_name VARCHAR(255) NOT NULL DEFAULT '',
+    from_status VARCHAR(20) NOT NULL DEFAULT '',
+    to_status VARCHAR(20) NOT NULL DEFAULT '',
+    after_lock BOOLEAN NOT NULL DEFAULT FALSE,
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_lineup_changes_event ON lineup_changes(event_id, changed_at);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    guild_id UUID REFERENCES guilds(id) ON DELETE CASCADE,
+    event_id UUID REFERENCES events(id) ON DELETE CASCADE,
+    kind VARCHAR(50) NOT NULL,
+    message TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    read_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications(user_id, created_at);
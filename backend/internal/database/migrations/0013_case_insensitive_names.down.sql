@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_characters_realm_name;
+CREATE UNIQUE INDEX idx_characters_realm_name ON characters(realm_id, name);
+
+DROP INDEX IF EXISTS idx_guilds_realm_name;
+CREATE UNIQUE INDEX idx_guilds_realm_name ON guilds(realm_id, name);
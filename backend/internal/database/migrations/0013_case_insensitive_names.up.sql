@@ -0,0 +1,8 @@
+-- Guild and character names are unique per realm regardless of case, as in
+-- game and as they are looked up. Creating the indexes fails if two rows of a
+-- realm differ only in case; merge those first.
+DROP INDEX IF EXISTS idx_guilds_realm_name;
+CREATE UNIQUE INDEX idx_guilds_realm_name ON guilds(realm_id, lower(name));
+
+DROP INDEX IF EXISTS idx_characters_realm_name;
+CREATE UNIQUE INDEX idx_characters_realm_name ON characters(realm_id, lower(name));
@@ -0,0 +1,295 @@
+package fixtures
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/realms"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+)
+
+// Summary counts the entities Apply wrote, whether created or updated.
+type Summary struct {
+	Users         int
+	Guilds        int
+	Characters    int
+	RaidGroups    int
+	Events        int
+	Confirmations int
+}
+
+func (s Summary) String() string {
+	return fmt.Sprintf("%d users, %d guilds, %d characters, %d raid groups, %d events, %d confirmations",
+		s.Users, s.Guilds, s.Characters, s.RaidGroups, s.Events, s.Confirmations)
+}
+
+// applier resolves symbolic keys to the IDs of the rows they were written to.
+type applier struct {
+	ctx context.Context
+	tx  *gorm.DB
+	set *Set
+	now time.Time
+
+	users      map[string]string
+	guilds     map[string]*models.Guild
+	characters map[string]string
+	raidGroups map[string]string
+	events     map[string]*models.Event
+}
+
+// Apply upserts the set in one transaction. Rows are matched by their
+// natural keys, so applying the same set twice changes nothing, and rows the
+// set does not mention are left alone.
+func Apply(ctx context.Context, db *gorm.DB, set *Set, now time.Time) (*Summary, error) {
+	var summary Summary
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		a := &applier{
+			ctx:        ctx,
+			tx:         tx,
+			set:        set,
+			now:        now.UTC(),
+			users:      map[string]string{},
+			guilds:     map[string]*models.Guild{},
+			characters: map[string]string{},
+			raidGroups: map[string]string{},
+			events:     map[string]*models.Event{},
+		}
+		for _, step := range []func() error{a.applyUsers, a.applyGuilds, a.applyCharacters, a.applyRaidGroups, a.applyEvents, a.applyConfirmations} {
+			if err := step(); err != nil {
+				return err
+			}
+		}
+		summary = Summary{
+			Users:         len(a.users),
+			Guilds:        len(a.guilds),
+			Characters:    len(a.characters),
+			RaidGroups:    len(a.raidGroups),
+			Events:        len(a.events),
+			Confirmations: len(set.Confirmations),
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+func (a *applier) applyUsers() error {
+	for _, key := range sortedKeys(a.set.Users) {
+		u := a.set.Users[key]
+		user := models.User{BattleNetID: u.BattleNetID, Username: u.Username, Email: u.Email, Role: u.Role}
+		if user.Role == "" {
+			user.Role = models.RoleMember
+		}
+		// Without an email the unique column must stay NULL, or users would
+		// collide on empty strings.
+		tx, columns := a.tx, []string{"username", "email", "role", "updated_at"}
+		if user.Email == "" {
+			tx, columns = tx.Omit("Email"), []string{"username", "role", "updated_at"}
+		}
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "battle_net_id"}},
+			DoUpdates: clause.AssignmentColumns(columns),
+		}).Create(&user).Error
+		if err != nil {
+			return fmt.Errorf("failed to seed user %q: %w", key, err)
+		}
+		a.users[key] = user.ID
+	}
+	return nil
+}
+
+func (a *applier) applyGuilds() error {
+	for _, key := range sortedKeys(a.set.Guilds) {
+		g := a.set.Guilds[key]
+		realm, err := realms.Resolve(a.tx, a.set.region(g.Region), g.Realm)
+		if err != nil {
+			return fmt.Errorf("failed to seed guild %q: %w", key, err)
+		}
+		guild := models.Guild{
+			Name:      g.Name,
+			RealmID:   realm.ID,
+			Realm:     realm.Name,
+			Faction:   g.Faction,
+			CreatedBy: a.users[g.CreatedBy],
+		}
+		err = a.tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "realm_id"}, {Name: "lower(name)", Raw: true}},
+			DoUpdates: clause.AssignmentColumns([]string{"realm", "faction", "created_by"}),
+		}).Create(&guild).Error
+		if err != nil {
+			return fmt.Errorf("failed to seed guild %q: %w", key, err)
+		}
+		a.guilds[key] = &guild
+
+		for _, user := range sortedKeys(g.Members) {
+			member := models.GuildMember{UserID: a.users[user], GuildID: guild.ID, JoinedAt: a.now, Role: g.Members[user]}
+			err := a.tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "user_id"}, {Name: "guild_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"role"}),
+			}).Create(&member).Error
+			if err != nil {
+				return fmt.Errorf("failed to add %q to guild %q: %w", user, key, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (a *applier) applyCharacters() error {
+	for _, key := range sortedKeys(a.set.Characters) {
+		c := a.set.Characters[key]
+		realm, err := realms.Resolve(a.tx, a.set.region(c.Region), c.Realm)
+		if err != nil {
+			return fmt.Errorf("failed to seed character %q: %w", key, err)
+		}
+		character := models.Character{
+			Name:    c.Name,
+			RealmID: realm.ID,
+			Realm:   realm.Name,
+			Class:   c.Class,
+			Spec:    c.Spec,
+			Ilvl:    c.Ilvl,
+			Level:   c.Level,
+			GuildID: a.guilds[c.Guild].ID,
+		}
+		if c.User != "" {
+			userID := a.users[c.User]
+			character.UserID = &userID
+		}
+		err = a.tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "realm_id"}, {Name: "lower(name)", Raw: true}},
+			DoUpdates: clause.AssignmentColumns([]string{"realm", "class", "spec", "ilvl", "level", "user_id", "guild_id", "updated_at"}),
+		}).Create(&character).Error
+		if err != nil {
+			return fmt.Errorf("failed to seed character %q: %w", key, err)
+		}
+		a.characters[key] = character.ID
+	}
+	return nil
+}
+
+// applyRaidGroups matches groups by guild and name, which no index enforces,
+// so it looks them up before writing.
+func (a *applier) applyRaidGroups() error {
+	for _, key := range sortedKeys(a.set.RaidGroups) {
+		rg := a.set.RaidGroups[key]
+		var group models.RaidGroup
+		err := a.tx.Where("guild_id = ? AND name = ?", a.guilds[rg.Guild].ID, rg.Name).
+			Order("created_at").Limit(1).Find(&group).Error
+		if err != nil {
+			return fmt.Errorf("failed to load raid group %q: %w", key, err)
+		}
+		group.Name = rg.Name
+		group.GuildID = a.guilds[rg.Guild].ID
+		group.Schedule = rg.Schedule
+		if group.ID == "" {
+			err = a.tx.Create(&group).Error
+		} else {
+			err = a.tx.Model(&group).Select("schedule").Updates(&group).Error
+		}
+		if err != nil {
+			return fmt.Errorf("failed to seed raid group %q: %w", key, err)
+		}
+		a.raidGroups[key] = group.ID
+
+		for _, char := range rg.Characters {
+			link := models.RaidGroupCharacter{CharacterID: a.characters[char], RaidGroupID: group.ID, JoinedAt: a.now}
+			if err := a.tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&link).Error; err != nil {
+				return fmt.Errorf("failed to add %q to raid group %q: %w", char, key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// applyEvents matches one-off events by guild, raid name and difficulty, and
+// asks their raid group's characters to respond.
+func (a *applier) applyEvents() error {
+	for _, key := range sortedKeys(a.set.Events) {
+		e := a.set.Events[key]
+		guildID := a.guilds[e.Guild].ID
+		var event models.Event
+		err := a.tx.Where("guild_id = ? AND raid_name = ? AND difficulty = ? AND occurrence_date IS NULL", guildID, e.RaidName, e.Difficulty).
+			Order("created_at").Limit(1).Find(&event).Error
+		if err != nil {
+			return fmt.Errorf("failed to load event %q: %w", key, err)
+		}
+
+		// Relative start times are only resolved for new events, so seeding
+		// again does not keep moving them.
+		if event.ID == "" || e.ScheduledAt != "" {
+			start, _ := e.start(a.now)
+			event.ScheduledAt = start
+		}
+		event.EndsAt = nil
+		if d, _ := e.duration(); d > 0 {
+			end := event.ScheduledAt.Add(d)
+			event.EndsAt = &end
+		}
+		event.RaidName = e.RaidName
+		event.Difficulty = e.Difficulty
+		event.GuildID = guildID
+		event.RaidGroupID = nil
+		if e.RaidGroup != "" {
+			groupID := a.raidGroups[e.RaidGroup]
+			event.RaidGroupID = &groupID
+		}
+		event.CreatedBy = nil
+		if e.CreatedBy != "" {
+			userID := a.users[e.CreatedBy]
+			event.CreatedBy = &userID
+		}
+
+		if event.ID == "" {
+			err = a.tx.Create(&event).Error
+		} else {
+			err = a.tx.Model(&event).
+				Select("scheduled_at", "ends_at", "raid_group_id", "created_by").
+				Updates(&event).Error
+		}
+		if err != nil {
+			return fmt.Errorf("failed to seed event %q: %w", key, err)
+		}
+		if err := repository.NewPostgresStore(a.tx).Confirmations().CreatePending(a.ctx, &event); err != nil {
+			return fmt.Errorf("failed to seed event %q: %w", key, err)
+		}
+		a.events[key] = &event
+	}
+	return nil
+}
+
+// applyConfirmations keeps the first response time of answers already given.
+func (a *applier) applyConfirmations() error {
+	for i, c := range a.set.Confirmations {
+		confirmation := models.Confirmation{
+			EventID:     a.events[c.Event].ID,
+			CharacterID: a.characters[c.Character],
+			Status:      c.Status,
+			Reason:      c.Reason,
+		}
+		if c.Status != models.ConfirmationPending {
+			confirmation.RespondedAt = &a.now
+		}
+		err := a.tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "event_id"}, {Name: "character_id"}},
+			DoUpdates: clause.Set{
+				{Column: clause.Column{Name: "status"}, Value: gorm.Expr("excluded.status")},
+				{Column: clause.Column{Name: "reason"}, Value: gorm.Expr("excluded.reason")},
+				{Column: clause.Column{Name: "responded_at"}, Value: gorm.Expr(
+					"CASE WHEN excluded.status = ? THEN NULL ELSE COALESCE(confirmations.responded_at, excluded.responded_at) END",
+					models.ConfirmationPending)},
+			},
+		}).Create(&confirmation).Error
+		if err != nil {
+			return fmt.Errorf("failed to seed confirmation %d (%s, %s): %w", i+1, c.Event, c.Character, err)
+		}
+	}
+	return nil
+}
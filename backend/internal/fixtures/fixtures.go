@@ -0,0 +1,425 @@
+// Package fixtures loads declarative seed data from YAML or JSON files and
+// upserts it, so seeding can be repeated without touching other data.
+package fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+)
+
+// Set is the content of one or more fixture files. Entities are keyed by
+// symbolic names that other entities use to reference them; the keys are
+// never stored.
+type Set struct {
+	// Region is the default region of guilds and characters.
+	Region        string               `json:"region"`
+	Users         map[string]User      `json:"users"`
+	Guilds        map[string]Guild     `json:"guilds"`
+	Characters    map[string]Character `json:"characters"`
+	RaidGroups    map[string]RaidGroup `json:"raid_groups"`
+	Events        map[string]Event     `json:"events"`
+	Confirmations []Confirmation       `json:"confirmations"`
+}
+
+// User is matched by its Battle.net ID.
+type User struct {
+	BattleNetID string `json:"battle_net_id"`
+	Username    string `json:"username"`
+	Email       string `json:"email"`
+	Role        string `json:"role"`
+}
+
+// Guild is matched by realm and name. Members maps user keys to guild roles.
+type Guild struct {
+	Name      string            `json:"name"`
+	Region    string            `json:"region"`
+	Realm     string            `json:"realm"`
+	Faction   string            `json:"faction"`
+	CreatedBy string            `json:"created_by"`
+	Members   map[string]string `json:"members"`
+}
+
+// Character is matched by realm and name.
+type Character struct {
+	Name   string `json:"name"`
+	Region string `json:"region"`
+	Realm  string `json:"realm"`
+	Class  string `json:"class"`
+	Spec   string `json:"spec"`
+	Ilvl   int    `json:"ilvl"`
+	Level  int    `json:"level"`
+	User   string `json:"user"`
+	Guild  string `json:"guild"`
+}
+
+// RaidGroup is matched by guild and name. Characters are added to the group
+// but never removed from it.
+type RaidGroup struct {
+	Name       string               `json:"name"`
+	Guild      string               `json:"guild"`
+	Schedule   *models.RaidSchedule `json:"schedule"`
+	Characters []string             `json:"characters"`
+}
+
+// Event is matched by guild, raid name and difficulty. It starts either at
+// ScheduledAt (RFC 3339) or StartsIn (a duration such as "36h") after the
+// event is first created; relative start times are not moved afterwards.
+type Event struct {
+	RaidName    string `json:"raid_name"`
+	Difficulty  string `json:"difficulty"`
+	Guild       string `json:"guild"`
+	RaidGroup   string `json:"raid_group"`
+	ScheduledAt string `json:"scheduled_at"`
+	StartsIn    string `json:"starts_in"`
+	Duration    string `json:"duration"`
+	CreatedBy   string `json:"created_by"`
+}
+
+// Confirmation is a character's answer to an event, matched by both.
+type Confirmation struct {
+	Event     string `json:"event"`
+	Character string `json:"character"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason"`
+}
+
+// Load reads the fixture files at paths, descending into directories for
+// their .yaml, .yml and .json files, and merges them into one validated Set.
+// A key defined by two files is an error.
+func Load(paths ...string) (*Set, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixtures: %w", err)
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list fixtures: %w", err)
+		}
+		for _, entry := range entries {
+			switch filepath.Ext(entry.Name()) {
+			case ".yaml", ".yml", ".json":
+				if !entry.IsDir() {
+					files = append(files, filepath.Join(path, entry.Name()))
+				}
+			}
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no fixture files found in %s", strings.Join(paths, ", "))
+	}
+
+	set := &Set{}
+	for _, file := range files {
+		part, err := readFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if err := set.merge(part); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	if err := set.validate(); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// readFile decodes a YAML or JSON file. YAML is converted to JSON first so
+// both formats share the json tags, and unknown fields are rejected to catch
+// typos.
+func readFile(path string) (*Set, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixtures: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	value, err := plain(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if data, err = json.Marshal(value); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	var set Set
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&set); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &set, nil
+}
+
+// plain converts a YAML node to maps, slices and scalars. Timestamps stay
+// strings, since the models parse dates and times themselves.
+func plain(n *yaml.Node) (any, error) {
+	switch n.Kind {
+	case 0:
+		return nil, nil
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return nil, nil
+		}
+		return plain(n.Content[0])
+	case yaml.AliasNode:
+		return plain(n.Alias)
+	case yaml.SequenceNode:
+		list := make([]any, len(n.Content))
+		for i, item := range n.Content {
+			v, err := plain(item)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = v
+		}
+		return list, nil
+	case yaml.MappingNode:
+		m := make(map[string]any, len(n.Content)/2)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			v, err := plain(n.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			m[n.Content[i].Value] = v
+		}
+		return m, nil
+	}
+	if n.ShortTag() == "!!timestamp" {
+		return n.Value, nil
+	}
+	var v any
+	if err := n.Decode(&v); err != nil {
+		return nil, fmt.Errorf("line %d: %w", n.Line, err)
+	}
+	return v, nil
+}
+
+func (s *Set) merge(o *Set) error {
+	if o.Region != "" {
+		if s.Region != "" && s.Region != o.Region {
+			return fmt.Errorf("region %q conflicts with %q", o.Region, s.Region)
+		}
+		s.Region = o.Region
+	}
+	var err error
+	if s.Users, err = mergeMap("user", s.Users, o.Users); err != nil {
+		return err
+	}
+	if s.Guilds, err = mergeMap("guild", s.Guilds, o.Guilds); err != nil {
+		return err
+	}
+	if s.Characters, err = mergeMap("character", s.Characters, o.Characters); err != nil {
+		return err
+	}
+	if s.RaidGroups, err = mergeMap("raid group", s.RaidGroups, o.RaidGroups); err != nil {
+		return err
+	}
+	if s.Events, err = mergeMap("event", s.Events, o.Events); err != nil {
+		return err
+	}
+	s.Confirmations = append(s.Confirmations, o.Confirmations...)
+	return nil
+}
+
+func mergeMap[T any](kind string, dst, src map[string]T) (map[string]T, error) {
+	if dst == nil {
+		dst = map[string]T{}
+	}
+	for key, v := range src {
+		if _, ok := dst[key]; ok {
+			return nil, fmt.Errorf("%s %q is defined twice", kind, key)
+		}
+		dst[key] = v
+	}
+	return dst, nil
+}
+
+// validate checks references and the values the database constrains, so a
+// bad file fails before anything is written.
+func (s *Set) validate() error {
+	var errs []string
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Sprintf(format, args...))
+	}
+
+	for _, key := range sortedKeys(s.Users) {
+		u := s.Users[key]
+		if u.BattleNetID == "" || u.Username == "" {
+			fail("user %q needs battle_net_id and username", key)
+		}
+		if u.Role != "" && !slices.Contains([]string{models.RoleMember, models.RoleOfficer, models.RoleAdmin}, u.Role) {
+			fail("user %q has unknown role %q", key, u.Role)
+		}
+	}
+	for _, key := range sortedKeys(s.Guilds) {
+		g := s.Guilds[key]
+		if g.Name == "" || g.Realm == "" {
+			fail("guild %q needs name and realm", key)
+		}
+		if !slices.Contains(models.Regions, s.region(g.Region)) {
+			fail("guild %q has unknown region %q", key, s.region(g.Region))
+		}
+		if g.Faction == "" {
+			fail("guild %q needs a faction", key)
+		} else if !slices.Contains(models.Factions, g.Faction) {
+			fail("guild %q has unknown faction %q", key, g.Faction)
+		}
+		if _, ok := s.Users[g.CreatedBy]; !ok {
+			fail("guild %q is created by unknown user %q", key, g.CreatedBy)
+		}
+		for _, user := range sortedKeys(g.Members) {
+			role := g.Members[user]
+			if _, ok := s.Users[user]; !ok {
+				fail("guild %q has unknown member %q", key, user)
+			}
+			if !slices.Contains([]string{models.GuildRoleMember, models.GuildRoleOfficer, models.GuildRoleGuildMaster}, role) {
+				fail("guild %q gives %q unknown role %q", key, user, role)
+			}
+		}
+	}
+	for _, key := range sortedKeys(s.Characters) {
+		c := s.Characters[key]
+		if c.Name == "" || c.Realm == "" {
+			fail("character %q needs name and realm", key)
+		}
+		if !slices.Contains(models.Regions, s.region(c.Region)) {
+			fail("character %q has unknown region %q", key, s.region(c.Region))
+		}
+		if !slices.Contains(models.Classes, c.Class) {
+			fail("character %q has unknown class %q", key, c.Class)
+		}
+		if _, ok := s.Guilds[c.Guild]; !ok {
+			fail("character %q belongs to unknown guild %q", key, c.Guild)
+		}
+		if _, ok := s.Users[c.User]; c.User != "" && !ok {
+			fail("character %q belongs to unknown user %q", key, c.User)
+		}
+	}
+	for _, key := range sortedKeys(s.RaidGroups) {
+		rg := s.RaidGroups[key]
+		if rg.Name == "" {
+			fail("raid group %q needs a name", key)
+		}
+		if _, ok := s.Guilds[rg.Guild]; !ok {
+			fail("raid group %q belongs to unknown guild %q", key, rg.Guild)
+		}
+		if rg.Schedule != nil {
+			if err := rg.Schedule.Validate(); err != nil {
+				fail("raid group %q: %v", key, err)
+			}
+		}
+		for _, char := range rg.Characters {
+			if c, ok := s.Characters[char]; !ok || c.Guild != rg.Guild {
+				fail("raid group %q lists %q, which is not a character of its guild", key, char)
+			}
+		}
+	}
+	for _, key := range sortedKeys(s.Events) {
+		e := s.Events[key]
+		if e.RaidName == "" {
+			fail("event %q needs a raid_name", key)
+		}
+		if !slices.Contains(models.Difficulties, e.Difficulty) {
+			fail("event %q has unknown difficulty %q", key, e.Difficulty)
+		}
+		if _, ok := s.Guilds[e.Guild]; !ok {
+			fail("event %q belongs to unknown guild %q", key, e.Guild)
+		}
+		if rg, ok := s.RaidGroups[e.RaidGroup]; e.RaidGroup != "" && (!ok || rg.Guild != e.Guild) {
+			fail("event %q uses %q, which is not a raid group of its guild", key, e.RaidGroup)
+		}
+		if _, ok := s.Users[e.CreatedBy]; e.CreatedBy != "" && !ok {
+			fail("event %q is created by unknown user %q", key, e.CreatedBy)
+		}
+		if (e.ScheduledAt == "") == (e.StartsIn == "") {
+			fail("event %q needs exactly one of scheduled_at and starts_in", key)
+		} else if _, err := e.start(time.Now()); err != nil {
+			fail("event %q: %v", key, err)
+		}
+		if _, err := e.duration(); err != nil {
+			fail("event %q: %v", key, err)
+		}
+	}
+	for i, c := range s.Confirmations {
+		e, ok := s.Events[c.Event]
+		if !ok {
+			fail("confirmation %d references unknown event %q", i+1, c.Event)
+		}
+		if char, found := s.Characters[c.Character]; !found || (ok && char.Guild != e.Guild) {
+			fail("confirmation %d references %q, which is not a character of the event's guild", i+1, c.Character)
+		}
+		if c.Status != models.ConfirmationPending && !slices.Contains(models.ConfirmationStatuses, c.Status) {
+			fail("confirmation %d has unknown status %q", i+1, c.Status)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid fixtures:\n  %s", strings.Join(errs, "\n  "))
+	}
+	return nil
+}
+
+func (s *Set) region(region string) string {
+	if region != "" {
+		return strings.ToLower(region)
+	}
+	return strings.ToLower(s.Region)
+}
+
+// start resolves the event's start time; relative times count from now.
+func (e Event) start(now time.Time) (time.Time, error) {
+	if e.StartsIn != "" {
+		d, err := time.ParseDuration(e.StartsIn)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid starts_in: %w", err)
+		}
+		return now.Add(d).Truncate(time.Minute), nil
+	}
+	t, err := time.Parse(time.RFC3339, e.ScheduledAt)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("scheduled_at must be an RFC 3339 timestamp")
+	}
+	return t, nil
+}
+
+// duration is the event's length, or 0 when it has no end time.
+func (e Event) duration() (time.Duration, error) {
+	if e.Duration == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(e.Duration)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("duration must be a positive duration such as 3h")
+	}
+	return d, nil
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,145 @@
+// Package lifecycle starts the background workers of the server and stops
+// them, together with the resources they use, in an orderly way on shutdown.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Worker is a background job that runs until its context is cancelled.
+type Worker interface {
+	Run(ctx context.Context)
+}
+
+// WorkerFunc adapts a function to the Worker interface.
+type WorkerFunc func(ctx context.Context)
+
+func (f WorkerFunc) Run(ctx context.Context) { f(ctx) }
+
+// HTTPServer runs srv as a worker, shutting it down gracefully when the
+// worker's context is cancelled.
+func HTTPServer(srv *http.Server, shutdownTimeout time.Duration) Worker {
+	return WorkerFunc(func(ctx context.Context) {
+		errc := make(chan error, 1)
+		go func() { errc <- srv.ListenAndServe() }()
+
+		select {
+		case err := <-errc:
+			if !errors.Is(err, http.ErrServerClosed) {
+				slog.Error("HTTP server failed", "addr", srv.Addr, "error", err)
+			}
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+			defer cancel()
+			if err := srv.Shutdown(shutdownCtx); err != nil {
+				slog.Error("HTTP server shutdown failed", "addr", srv.Addr, "error", err)
+			}
+		}
+	})
+}
+
+type namedWorker struct {
+	name   string
+	worker Worker
+}
+
+type closer struct {
+	name  string
+	close func(ctx context.Context) error
+}
+
+// Manager runs registered workers until Stop, then closes registered
+// resources in reverse order of registration, so a resource registered
+// before a worker outlives it.
+type Manager struct {
+	workers []namedWorker
+	closers []closer
+
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	stopped bool
+}
+
+// New returns an empty Manager.
+func New() *Manager {
+	return &Manager{}
+}
+
+// Add registers a worker to run once Start is called.
+func (m *Manager) Add(name string, w Worker) {
+	m.workers = append(m.workers, namedWorker{name: name, worker: w})
+}
+
+// OnStop registers a function that releases a resource during Stop, after
+// every worker returned.
+func (m *Manager) OnStop(name string, fn func(ctx context.Context) error) {
+	m.closers = append(m.closers, closer{name: name, close: fn})
+}
+
+// Start runs every worker in its own goroutine with a context derived from
+// ctx. A worker that panics is logged instead of taking the server down.
+func (m *Manager) Start(ctx context.Context) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ctx, m.cancel = context.WithCancel(ctx)
+	for _, w := range m.workers {
+		m.wg.Add(1)
+		go func(w namedWorker) {
+			defer m.wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					slog.Error("worker panicked", "worker", w.name, "panic", r)
+				}
+			}()
+			slog.Info("worker started", "worker", w.name)
+			w.worker.Run(ctx)
+			slog.Info("worker stopped", "worker", w.name)
+		}(w)
+	}
+}
+
+// Stop cancels the workers, waits for them until ctx is done and then runs
+// the stop functions. It returns every error met along the way; calling it
+// again does nothing.
+func (m *Manager) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	if m.stopped {
+		m.mu.Unlock()
+		return nil
+	}
+	m.stopped = true
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.mu.Unlock()
+
+	var errs []error
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		errs = append(errs, fmt.Errorf("workers did not stop in time: %w", ctx.Err()))
+	}
+
+	for i := len(m.closers) - 1; i >= 0; i-- {
+		c := m.closers[i]
+		if err := c.close(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close %s: %w", c.name, err))
+			continue
+		}
+		slog.Info("closed resource", "resource", c.name)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,85 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// slowQuery is the duration above which statements are logged as warnings.
+const slowQuery = 200 * time.Millisecond
+
+// GORMLogger sends GORM's logs to slog. Statements are logged at debug level,
+// slow ones as warnings and failed ones as errors, each with the request ID
+// of the context the query ran with.
+type GORMLogger struct {
+	level gormlogger.LogLevel
+}
+
+// NewGORMLogger returns a GORM logger that logs everything slog's default
+// logger accepts.
+func NewGORMLogger() *GORMLogger {
+	return &GORMLogger{level: gormlogger.Info}
+}
+
+func (l *GORMLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	return &GORMLogger{level: level}
+}
+
+func (l *GORMLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Info {
+		slog.InfoContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *GORMLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		slog.WarnContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *GORMLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Error {
+		slog.ErrorContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *GORMLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	level := slog.LevelDebug
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= gormlogger.Error:
+		level = slog.LevelError
+	case elapsed > slowQuery && l.level >= gormlogger.Warn:
+		level = slog.LevelWarn
+	}
+	logger := slog.Default()
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	sql, rows := fc()
+	attrs := []slog.Attr{
+		slog.String("sql", sql),
+		slog.Int64("rows", rows),
+		slog.Float64("duration_ms", float64(elapsed.Microseconds())/1000),
+	}
+	msg := "query"
+	switch level {
+	case slog.LevelError:
+		msg = "query failed"
+		attrs = append(attrs, slog.Any("error", err))
+	case slog.LevelWarn:
+		msg = "slow query"
+	}
+	logger.LogAttrs(ctx, level, msg, attrs...)
+}
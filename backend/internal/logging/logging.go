@@ -0,0 +1,129 @@
+// Package logging configures log/slog for the server and the command-line
+// tools: JSON or text output, a level, the request ID of the current request
+// on every record, and redaction of secrets.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Formats lists the accepted output formats.
+var Formats = []string{"json", "text"}
+
+// Levels lists the accepted levels.
+var Levels = []string{"debug", "info", "warn", "error"}
+
+// New returns a logger writing to w at level in format, which must be one of
+// Levels and Formats.
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl, ReplaceAttr: redactAttr}
+	var h slog.Handler
+	switch strings.ToLower(format) {
+	case "json":
+		h = slog.NewJSONHandler(w, opts)
+	case "text":
+		h = slog.NewTextHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("invalid log format %q", format)
+	}
+	return slog.New(contextHandler{h}), nil
+}
+
+// Setup makes a logger writing to stderr the default, which also routes the
+// standard log package through it.
+func Setup(level, format string) error {
+	logger, err := New(os.Stderr, level, format)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(logger)
+	return nil
+}
+
+// Fatal logs msg at error level and exits with status 1.
+func Fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context whose log records carry id.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID stored in ctx, or "".
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// contextHandler adds the request ID of the record's context, if any.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+const redacted = "[REDACTED]"
+
+// secretKeys are attribute names whose values are never logged.
+var secretKeys = []string{"password", "secret", "token", "authorization", "cookie", "dsn"}
+
+// secretPatterns match secrets embedded in free text: key=value pairs of
+// connection strings and the password of URL user info.
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\b(password|secret|token)=\S+`),
+	regexp.MustCompile(`(://[^:/@\s]+:)[^@\s]+@`),
+}
+
+// Redact masks secrets in s, such as the password of a DSN.
+func Redact(s string) string {
+	s = secretPatterns[0].ReplaceAllString(s, "${1}="+redacted)
+	return secretPatterns[1].ReplaceAllString(s, "${1}"+redacted+"@")
+}
+
+func redactAttr(_ []string, a slog.Attr) slog.Attr {
+	key := strings.ToLower(a.Key)
+	for _, k := range secretKeys {
+		if strings.Contains(key, k) {
+			return slog.String(a.Key, redacted)
+		}
+	}
+	switch a.Value.Kind() {
+	case slog.KindString:
+		if s := a.Value.String(); s != "" {
+			return slog.String(a.Key, Redact(s))
+		}
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, Redact(err.Error()))
+		}
+	}
+	return a
+}
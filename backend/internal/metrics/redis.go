@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisHook counts failed Redis commands. Register it with AddHook. A missing
+// key (redis.Nil) is not a failure.
+type RedisHook struct{}
+
+func (RedisHook) BeforeProcess(ctx context.Context, _ redis.Cmder) (context.Context, error) {
+	return ctx, nil
+}
+
+func (RedisHook) AfterProcess(_ context.Context, cmd redis.Cmder) error {
+	countRedisError(cmd)
+	return nil
+}
+
+func (RedisHook) BeforeProcessPipeline(ctx context.Context, _ []redis.Cmder) (context.Context, error) {
+	return ctx, nil
+}
+
+func (RedisHook) AfterProcessPipeline(_ context.Context, cmds []redis.Cmder) error {
+	for _, cmd := range cmds {
+		countRedisError(cmd)
+	}
+	return nil
+}
+
+func countRedisError(cmd redis.Cmder) {
+	if err := cmd.Err(); err != nil && !errors.Is(err, redis.Nil) {
+		redisErrors.WithLabelValues(cmd.Name()).Inc()
+	}
+}
@@ -0,0 +1,225 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/auth"
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+)
+
+// Permission is an action guarded by the authorization middleware.
+type Permission string
+
+const (
+	PermViewGuild        Permission = "view_guild"
+	PermRSVP             Permission = "rsvp"
+	PermManageCharacters Permission = "manage_characters"
+	PermManageEvents     Permission = "manage_events"
+	PermManageRaidGroups Permission = "manage_raid_groups"
+	PermRecordAttendance Permission = "record_attendance"
+	PermSyncGuild        Permission = "sync_guild"
+	PermManageOfficers   Permission = "manage_officers"
+	PermManageGuild      Permission = "manage_guild"
+)
+
+// Denial reasons returned in the "reason" field of 401/403 responses.
+const (
+	ReasonUnauthenticated   = "unauthenticated"
+	ReasonNotGuildMember    = "not_guild_member"
+	ReasonInsufficientRole  = "insufficient_role"
+	ReasonNotCharacterOwner = "not_character_owner"
+)
+
+// rolePermissions is the permission matrix for guild roles. Site admins are
+// allowed everything and are not listed.
+var rolePermissions = map[string][]Permission{
+	models.GuildRoleMember: {
+		PermViewGuild, PermRSVP,
+	},
+	models.GuildRoleOfficer: {
+		PermViewGuild, PermRSVP, PermManageCharacters, PermManageEvents,
+		PermManageRaidGroups, PermRecordAttendance, PermSyncGuild,
+	},
+	models.GuildRoleGuildMaster: {
+		PermViewGuild, PermRSVP, PermManageCharacters, PermManageEvents,
+		PermManageRaidGroups, PermRecordAttendance, PermSyncGuild, PermManageOfficers,
+		PermManageGuild,
+	},
+}
+
+// RoleAllows reports whether a guild role grants a permission.
+func RoleAllows(role string, perm Permission) bool {
+	return slices.Contains(rolePermissions[role], perm)
+}
+
+const (
+	userContextKey   = "current_user"
+	memberContextKey = "guild_member"
+)
+
+// Authorizer enforces guild permissions for the session's user.
+type Authorizer struct {
+	store    repository.Store
+	sessions *auth.SessionStore
+}
+
+// NewAuthorizer creates an Authorizer.
+func NewAuthorizer(store repository.Store, sessions *auth.SessionStore) *Authorizer {
+	return &Authorizer{store: store, sessions: sessions}
+}
+
+// RequireGuildPermission returns a middleware that resolves the current user
+// from the session, loads their guild_members row for the guild named by the
+// route parameter param and checks perm against the permission matrix.
+func (a *Authorizer) RequireGuildPermission(param string, perm Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if a.Authorize(c, param, perm) {
+			c.Next()
+		}
+	}
+}
+
+// Authorize runs the checks of RequireGuildPermission without calling the
+// next handler, for middleware with more to do once perm is granted. When it
+// returns false the request has been aborted.
+func (a *Authorizer) Authorize(c *gin.Context, param string, perm Permission) bool {
+	user, ok := a.resolveUser(c)
+	if !ok {
+		return false
+	}
+	if !ValidID(c.Param(param)) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "guild not found", "code": "not_found"})
+		return false
+	}
+
+	member, allowed, err := a.check(c.Request.Context(), user, c.Param(param), perm)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to check permissions", "code": "internal_error"})
+		return false
+	}
+	if !allowed {
+		deny(c, member, perm)
+		return false
+	}
+
+	if member != nil {
+		c.Set(memberContextKey, member)
+	}
+	return true
+}
+
+// Can reports whether a user holds perm in a guild, for handlers that only
+// learn the guild while processing the request.
+func (a *Authorizer) Can(ctx context.Context, userID, guildID string, perm Permission) (bool, error) {
+	if !ValidID(guildID) {
+		return false, nil
+	}
+	user, err := a.store.Users().Get(ctx, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	_, allowed, err := a.check(ctx, user, guildID, perm)
+	return allowed, err
+}
+
+// HasPermission reports whether the user resolved by the middleware holds
+// perm in the guild of the current request.
+func HasPermission(c *gin.Context, perm Permission) bool {
+	if user := CurrentUser(c); user != nil && user.Role == models.RoleAdmin {
+		return true
+	}
+	member, ok := c.Get(memberContextKey)
+	return ok && RoleAllows(member.(*models.GuildMember).Role, perm)
+}
+
+// CurrentUser returns the user resolved by the middleware, if any.
+func CurrentUser(c *gin.Context) *models.User {
+	if user, ok := c.Get(userContextKey); ok {
+		return user.(*models.User)
+	}
+	return nil
+}
+
+// resolveUser loads the session's user, answering 401 when there is none.
+func (a *Authorizer) resolveUser(c *gin.Context) (*models.User, bool) {
+	if user := CurrentUser(c); user != nil {
+		return user, true
+	}
+
+	sess, err := a.sessions.FromRequest(c)
+	if err != nil && !errors.Is(err, auth.ErrNoSession) {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "session lookup failed", "code": "internal_error"})
+		return nil, false
+	}
+
+	var user *models.User
+	if err == nil {
+		user, err = a.store.Users().Get(c.Request.Context(), sess.UserID)
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"error":  "authentication required",
+			"code":   "unauthorized",
+			"reason": ReasonUnauthenticated,
+		})
+		return nil, false
+	}
+
+	c.Set(userContextKey, user)
+	return user, true
+}
+
+// check loads the membership and evaluates the matrix. A nil member with
+// allowed=false means the user is not in the guild.
+func (a *Authorizer) check(ctx context.Context, user *models.User, guildID string, perm Permission) (*models.GuildMember, bool, error) {
+	member, err := a.store.Guilds().Member(ctx, guildID, user.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, user.Role == models.RoleAdmin, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return member, user.Role == models.RoleAdmin || RoleAllows(member.Role, perm), nil
+}
+
+// ValidID reports whether id is a UUID, the form of every guild ID. Postgres
+// rejects lookups by anything else with an error rather than no rows.
+func ValidID(id string) bool {
+	if len(id) != 36 {
+		return false
+	}
+	for i, r := range id {
+		switch {
+		case i == 8 || i == 13 || i == 18 || i == 23:
+			if r != '-' {
+				return false
+			}
+		case !strings.ContainsRune("0123456789abcdefABCDEF", r):
+			return false
+		}
+	}
+	return true
+}
+
+func deny(c *gin.Context, member *models.GuildMember, perm Permission) {
+	reason, message := ReasonInsufficientRole, "your guild role does not allow this action"
+	if member == nil {
+		reason, message = ReasonNotGuildMember, "you are not a member of this guild"
+	}
+	c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+		"error":      message,
+		"code":       "forbidden",
+		"reason":     reason,
+		"permission": perm,
+	})
+}
@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"html/template"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/auth"
+)
+
+const (
+	// CSRFCookie carries the CSRF token. It is readable by scripts so that
+	// pages can echo it in CSRFHeader.
+	CSRFCookie = "gm_csrf"
+	// CSRFHeader and CSRFField are where state-changing requests submit
+	// the token: the header for HTMX and fetch, the field for plain forms.
+	CSRFHeader = "X-CSRF-Token"
+	CSRFField  = "csrf_token"
+
+	// csrfContextKey is the gin context key holding the request's token.
+	csrfContextKey = "csrf_token"
+)
+
+// safeMethods do not change state and are never checked.
+var safeMethods = []string{http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace}
+
+// CSRF protects cookie-authenticated requests with signed double-submit
+// tokens. A token is a random nonce plus an HMAC of the nonce and the session
+// cookie made with key, so it is only valid for the session it was issued
+// in. The token is set in CSRFCookie whenever the request lacks a valid one,
+// and POST, PUT, PATCH and DELETE requests must echo it in CSRFHeader or
+// CSRFField, or are answered 403.
+//
+// Requests without a session cookie are not checked: they carry no
+// credentials for a forged request to ride on. Neither are the exempt paths.
+func CSRF(key string, secure bool, exempt ...string) gin.HandlerFunc {
+	mac := func(nonce, session string) string {
+		h := hmac.New(sha256.New, []byte(key))
+		h.Write([]byte(nonce + "|" + session))
+		return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+	}
+	valid := func(token, session string) bool {
+		nonce, sig, ok := strings.Cut(token, ".")
+		return ok && nonce != "" && hmac.Equal([]byte(sig), []byte(mac(nonce, session)))
+	}
+
+	return func(c *gin.Context) {
+		if slices.Contains(exempt, c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		session, _ := c.Cookie(auth.SessionCookie)
+		check := session != "" && !slices.Contains(safeMethods, c.Request.Method)
+		token, _ := c.Cookie(CSRFCookie)
+		if !valid(token, session) {
+			if check {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "missing or invalid CSRF token"})
+				return
+			}
+			b := make([]byte, 18)
+			_, _ = rand.Read(b)
+			nonce := base64.RawURLEncoding.EncodeToString(b)
+			token = nonce + "." + mac(nonce, session)
+			c.SetSameSite(http.SameSiteLaxMode)
+			c.SetCookie(CSRFCookie, token, 0, "/", "", secure, false)
+		}
+		c.Set(csrfContextKey, token)
+
+		if check {
+			submitted := c.GetHeader(CSRFHeader)
+			if submitted == "" {
+				submitted = c.PostForm(CSRFField)
+			}
+			if !hmac.Equal([]byte(submitted), []byte(token)) {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "missing or invalid CSRF token"})
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// CSRFToken returns the CSRF token of the request, for templates to embed in
+// forms.
+func CSRFToken(c *gin.Context) string {
+	return c.GetString(csrfContextKey)
+}
+
+// CSRFTemplateData adds the request's CSRF token to the data of a template:
+// CSRFToken for hidden CSRFField inputs, and CSRFHeaders, an hx-headers
+// attribute that makes HTMX send the token with every request issued from
+// inside the element carrying it.
+func CSRFTemplateData(c *gin.Context, data gin.H) gin.H {
+	if data == nil {
+		data = gin.H{}
+	}
+	token := CSRFToken(c)
+	data["CSRFToken"] = token
+	// Tokens are base64url, so they need no escaping inside the attribute.
+	data["CSRFHeaders"] = template.HTMLAttr(`hx-headers='{"` + CSRFHeader + `": "` + token + `"}'`)
+	return data
+}
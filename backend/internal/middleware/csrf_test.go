@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/auth"
+)
+
+func TestCSRF(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CSRF("test-key", false, "/livez"))
+	router.GET("/", func(c *gin.Context) { c.String(http.StatusOK, CSRFToken(c)) })
+	router.POST("/", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	router.POST("/livez", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	session := &http.Cookie{Name: auth.SessionCookie, Value: "session"}
+	do := func(method, path, token string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+		if token != "" {
+			req.Header.Set(CSRFHeader, token)
+		}
+		req.Header.Set("Authorization", "Bearer forged")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	token := do(http.MethodGet, "/", "", session).Body.String()
+	if token == "" {
+		t.Fatal("no token issued")
+	}
+	csrf := &http.Cookie{Name: CSRFCookie, Value: token}
+	other := &http.Cookie{Name: auth.SessionCookie, Value: "other"}
+
+	tests := []struct {
+		name    string
+		path    string
+		token   string
+		cookies []*http.Cookie
+		want    int
+	}{
+		{"session without token", "/", "", []*http.Cookie{session, csrf}, http.StatusForbidden},
+		{"session with token", "/", token, []*http.Cookie{session, csrf}, http.StatusNoContent},
+		{"token of another session", "/", token, []*http.Cookie{other, csrf}, http.StatusForbidden},
+		{"no session", "/", "", nil, http.StatusNoContent},
+		{"exempt path", "/livez", "", []*http.Cookie{session}, http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := do(http.MethodPost, tt.path, tt.token, tt.cookies...); w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
@@ -1,43 +1,182 @@
 package middleware
 
 import (
+	"fmt"
+	"log/slog"
 	"net/http"
+	"slices"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-redis/redis/v8"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/auth"
+	"github.com/GFerreiroS/guild-manager/backend/internal/metrics"
 )
 
-// RateLimitMiddleware returns a Gin middleware function that limits requests.
-func RateLimitMiddleware(rdb *redis.Client, requestsPerMinute int) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		ip := c.ClientIP()
-		key := "rate_limit:" + ip
+// Rate limiting algorithms.
+const (
+	// SlidingWindow allows Limit requests in any Window long period.
+	SlidingWindow = "sliding_window"
+	// TokenBucket allows bursts of Limit requests and refills Limit tokens
+	// per Window.
+	TokenBucket = "token_bucket"
+)
+
+// RateLimitAlgorithms lists the accepted algorithms.
+var RateLimitAlgorithms = []string{SlidingWindow, TokenBucket}
+
+// Both scripts use the Redis clock so replicas agree on time, and return
+// {allowed, remaining, retry after ms, reset ms}.
+var (
+	// slidingWindowScript keeps the timestamps of the requests of the last
+	// window in a sorted set.
+	slidingWindowScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local t = redis.call("TIME")
+local now = t[1] * 1000 + math.floor(t[2] / 1000)
+
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now - window)
+local count = redis.call("ZCARD", KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call("ZADD", KEYS[1], now, now .. "-" .. ARGV[3])
+	redis.call("PEXPIRE", KEYS[1], window)
+	count = count + 1
+	allowed = 1
+end
+
+local reset = window
+local oldest = redis.call("ZRANGE", KEYS[1], 0, 0, "WITHSCORES")
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+local retry = 0
+if allowed == 0 then
+	retry = reset
+end
+return {allowed, limit - count, retry, reset}`)
+
+	// tokenBucketScript stores the tokens left and the time of the last
+	// refill in a hash.
+	tokenBucketScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local rate = capacity / window
+local t = redis.call("TIME")
+local now = t[1] * 1000 + math.floor(t[2] / 1000)
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1]) or capacity
+local ts = tonumber(state[2]) or now
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", now)
+redis.call("PEXPIRE", KEYS[1], window)
+
+local retry = 0
+if allowed == 0 then
+	retry = math.ceil((1 - tokens) / rate)
+end
+return {allowed, math.floor(tokens), retry, math.ceil((capacity - tokens) / rate)}`)
+)
+
+// RatePolicy is a request budget shared by the routes it is applied to.
+type RatePolicy struct {
+	// Name keeps the counters of different policies apart.
+	Name      string
+	Limit     int
+	Window    time.Duration
+	Algorithm string
+}
+
+// RateLimiter enforces rate policies in Redis, so every replica shares the
+// same budgets. Signed-in users are limited by user ID, everyone else by
+// client IP.
+type RateLimiter struct {
+	rdb      *redis.Client
+	sessions *auth.SessionStore
+	failOpen bool
+}
 
-		// Increment the request counter.
-		count, err := rdb.Incr(c, key).Result()
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "rate limiter error"})
+// NewRateLimiter returns a limiter backed by rdb. sessions may be nil, in
+// which case every client is limited by IP. With failOpen, requests are let
+// through while Redis is unavailable instead of being answered 503.
+func NewRateLimiter(rdb *redis.Client, sessions *auth.SessionStore, failOpen bool) *RateLimiter {
+	return &RateLimiter{rdb: rdb, sessions: sessions, failOpen: failOpen}
+}
+
+// Limit returns a middleware that enforces p on every request except those
+// to the exempt paths. It sets the RateLimit-Limit, RateLimit-Remaining,
+// RateLimit-Reset and RateLimit-Policy headers, and Retry-After on 429.
+func (l *RateLimiter) Limit(p RatePolicy, exempt ...string) gin.HandlerFunc {
+	script := slidingWindowScript
+	if p.Algorithm == TokenBucket {
+		script = tokenBucketScript
+	}
+	policy := fmt.Sprintf("%d;w=%d", p.Limit, int(p.Window.Seconds()))
+
+	return func(c *gin.Context) {
+		if slices.Contains(exempt, c.Request.URL.Path) {
+			c.Next()
 			return
 		}
 
-		// Set expiration on the key if it's the first request.
-		if count == 1 {
-			rdb.Expire(c, key, time.Minute)
+		key := "rate_limit:" + p.Name + ":" + l.subject(c)
+		res, err := script.Run(c.Request.Context(), l.rdb, []string{key},
+			p.Limit, p.Window.Milliseconds(), newRequestID()).Int64Slice()
+		if err != nil || len(res) != 4 {
+			slog.WarnContext(c.Request.Context(), "rate limiter unavailable",
+				"policy", p.Name, "fail_open", l.failOpen, "error", err)
+			if l.failOpen {
+				c.Next()
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "rate limiter unavailable"})
+			return
 		}
+		allowed, remaining, retryAfter, reset := res[0] == 1, res[1], res[2], res[3]
 
-		// If the count exceeds the limit, abort with 429 status.
-		if count > int64(requestsPerMinute) {
+		c.Header("RateLimit-Limit", strconv.Itoa(p.Limit))
+		c.Header("RateLimit-Remaining", strconv.FormatInt(max(remaining, 0), 10))
+		c.Header("RateLimit-Reset", strconv.FormatInt(ceilSeconds(reset), 10))
+		c.Header("RateLimit-Policy", policy)
+
+		if !allowed {
+			metrics.RateLimitRejections.WithLabelValues(p.Name).Inc()
+			seconds := ceilSeconds(retryAfter)
+			c.Header("Retry-After", strconv.FormatInt(seconds, 10))
 			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
 				"error":       "rate limit exceeded",
-				"retry_after": "60 seconds",
+				"retry_after": seconds,
 			})
 			return
 		}
-
-		// Optionally, set a header with remaining requests.
-		c.Header("X-RateLimit-Remaining", strconv.Itoa(requestsPerMinute-int(count)))
 		c.Next()
 	}
 }
+
+// subject identifies the client a budget belongs to.
+func (l *RateLimiter) subject(c *gin.Context) string {
+	if l.sessions != nil {
+		if sess, err := l.sessions.FromRequest(c); err == nil {
+			return "user:" + sess.UserID
+		}
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// ceilSeconds rounds a duration in milliseconds up to whole seconds.
+func ceilSeconds(ms int64) int64 {
+	if ms <= 0 {
+		return 0
+	}
+	return (ms + 999) / 1000
+}
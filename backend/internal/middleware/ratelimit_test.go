@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/auth"
+)
+
+// newLimitedRouter serves /, limited to 3 requests a minute, and the exempt
+// /livez from a limiter backed by mr.
+func newLimitedRouter(t *testing.T, mr *miniredis.Miniredis, algorithm string, failOpen bool) (*gin.Engine, *auth.SessionStore) {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { _ = rdb.Close() })
+	sessions := auth.NewSessionStore(rdb, "test-secret", time.Hour, false)
+
+	limiter := NewRateLimiter(rdb, sessions, failOpen)
+	router := gin.New()
+	router.Use(limiter.Limit(RatePolicy{Name: "test", Limit: 3, Window: time.Minute, Algorithm: algorithm}, "/livez"))
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	router.GET("/livez", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	return router, sessions
+}
+
+func get(router *gin.Engine, path, ip string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = ip + ":1234"
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimit(t *testing.T) {
+	// Every request is sent at the same instant. A sliding window frees
+	// nothing before they are a minute old, while a token bucket refills a
+	// token every 20 seconds.
+	tests := []struct {
+		algorithm       string
+		retryAfter      string
+		allowedAfter25s bool
+	}{
+		{SlidingWindow, "60", false},
+		{TokenBucket, "20", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			mr := miniredis.RunT(t)
+			start := time.Date(2026, 10, 18, 20, 0, 0, 0, time.UTC)
+			mr.SetTime(start)
+			router, _ := newLimitedRouter(t, mr, tt.algorithm, false)
+
+			for i, remaining := range []string{"2", "1", "0"} {
+				w := get(router, "/", "192.0.2.1")
+				if w.Code != http.StatusNoContent {
+					t.Fatalf("request %d: status = %d, want %d", i+1, w.Code, http.StatusNoContent)
+				}
+				if got := w.Header().Get("RateLimit-Remaining"); got != remaining {
+					t.Fatalf("request %d: RateLimit-Remaining = %q, want %q", i+1, got, remaining)
+				}
+			}
+
+			w := get(router, "/", "192.0.2.1")
+			if w.Code != http.StatusTooManyRequests {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusTooManyRequests)
+			}
+			for header, want := range map[string]string{
+				"RateLimit-Limit":     "3",
+				"RateLimit-Remaining": "0",
+				"RateLimit-Policy":    "3;w=60",
+				"Retry-After":         tt.retryAfter,
+			} {
+				if got := w.Header().Get(header); got != want {
+					t.Errorf("%s = %q, want %q", header, got, want)
+				}
+			}
+			if got := w.Header().Get("RateLimit-Reset"); got == "" || got == "0" {
+				t.Errorf("RateLimit-Reset = %q, want the seconds until a request is freed", got)
+			}
+
+			// Budgets are per client, and exempt paths are never limited.
+			if w := get(router, "/", "192.0.2.2"); w.Code != http.StatusNoContent {
+				t.Fatalf("other client: status = %d, want %d", w.Code, http.StatusNoContent)
+			}
+			if w := get(router, "/livez", "192.0.2.1"); w.Code != http.StatusNoContent {
+				t.Fatalf("exempt path: status = %d, want %d", w.Code, http.StatusNoContent)
+			}
+
+			mr.SetTime(start.Add(25 * time.Second))
+			if allowed := get(router, "/", "192.0.2.1").Code == http.StatusNoContent; allowed != tt.allowedAfter25s {
+				t.Fatalf("allowed after 25s = %v, want %v", allowed, tt.allowedAfter25s)
+			}
+
+			mr.SetTime(start.Add(2 * time.Minute))
+			if w := get(router, "/", "192.0.2.1"); w.Code != http.StatusNoContent {
+				t.Fatalf("after the window: status = %d, want %d", w.Code, http.StatusNoContent)
+			}
+		})
+	}
+}
+
+func TestRateLimitBySession(t *testing.T) {
+	mr := miniredis.RunT(t)
+	router, sessions := newLimitedRouter(t, mr, SlidingWindow, false)
+
+	_, value, err := sessions.Create(context.Background(), "user-1", "1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cookie := &http.Cookie{Name: auth.SessionCookie, Value: value}
+
+	// Signed-in users keep their budget when their address changes.
+	for _, ip := range []string{"192.0.2.1", "192.0.2.2", "192.0.2.3"} {
+		if w := get(router, "/", ip, cookie); w.Code != http.StatusNoContent {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusNoContent)
+		}
+	}
+	if w := get(router, "/", "192.0.2.4", cookie); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+}
+
+func TestRateLimitRedisDown(t *testing.T) {
+	tests := []struct {
+		name     string
+		failOpen bool
+		want     int
+	}{
+		{"fail open", true, http.StatusNoContent},
+		{"fail closed", false, http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mr := miniredis.RunT(t)
+			router, _ := newLimitedRouter(t, mr, SlidingWindow, tt.failOpen)
+			mr.Close()
+
+			w := get(router, "/", "192.0.2.1")
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+			if got := w.Header().Get("RateLimit-Limit"); got != "" {
+				t.Fatalf("RateLimit-Limit = %q without a budget", got)
+			}
+			if w := get(router, "/livez", "192.0.2.1"); w.Code != http.StatusNoContent {
+				t.Fatalf("exempt path: status = %d, want %d", w.Code, http.StatusNoContent)
+			}
+		})
+	}
+}
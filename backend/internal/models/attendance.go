@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+)
+
+// Attendance is what actually happened at an event for a character, recorded
+// by officers after the raid. Compare with the Confirmation, which is only
+// the member's intent.
+type Attendance struct {
+	ID          string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	EventID     string    `gorm:"type:uuid;not null;uniqueIndex:idx_attendances_event_character" json:"event_id"`
+	CharacterID string    `gorm:"type:uuid;not null;uniqueIndex:idx_attendances_event_character;index" json:"character_id"`
+	Status      string    `gorm:"type:varchar(50);not null;check:status IN ('present','late','absent','benched','excused')" json:"status"`
+	Note        string    `gorm:"type:text" json:"note"`
+	RecordedBy  *string   `gorm:"type:uuid" json:"recorded_by"`
+	RecordedAt  time.Time `gorm:"type:timestamptz;not null" json:"recorded_at"`
+
+	Event     Event     `gorm:"foreignKey:EventID" json:"-"`
+	Character Character `gorm:"foreignKey:CharacterID" json:"-"`
+}
+
+// Attendance statuses. Benched characters were available but not needed and
+// count as attended; excused absences are left out of attendance percentages.
+const (
+	AttendancePresent = "present"
+	AttendanceLate    = "late"
+	AttendanceAbsent  = "absent"
+	AttendanceBenched = "benched"
+	AttendanceExcused = "excused"
+)
+
+// AttendanceStatuses lists the values allowed by the attendances.status CHECK
+// constraint.
+var AttendanceStatuses = []string{AttendancePresent, AttendanceLate, AttendanceAbsent, AttendanceBenched, AttendanceExcused}
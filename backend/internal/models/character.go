@@ -5,21 +5,35 @@ import (
 )
 
 type Character struct {
-	ID          string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
-	Name        string    `gorm:"type:varchar(255);not null"`
-	Realm       string    `gorm:"type:varchar(255);not null"`
-	Class       string    `gorm:"type:varchar(50);not null;check:class IN ('warrior','paladin','hunter','rogue','priest','death-knight','shaman','mage','warlock','monk','druid','demon-hunter','evoker')"`
-	Spec        string    `gorm:"type:varchar(50)"`
-	Ilvl        int       `gorm:"not null"`
-	LastSynced  time.Time `gorm:"type:timestamptz"`
-	UserID      string    `gorm:"type:uuid;not null"`
-	GuildID     string    `gorm:"type:uuid;not null"`
-	RaidGroupID *string   `gorm:"type:uuid"` // Changed to pointer so that nil (NULL) is stored if not set
-	CreatedAt   time.Time `gorm:"autoCreateTime"`
-	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+	ID            string     `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	Name          string     `gorm:"type:varchar(255);not null;uniqueIndex:idx_characters_realm_name,expression:lower(name)" json:"name"`
+	RealmID       string     `gorm:"type:uuid;not null;uniqueIndex:idx_characters_realm_name" json:"realm_id"`
+	Realm         string     `gorm:"type:varchar(255);not null" json:"realm"` // Display name of RealmID
+	Class         string     `gorm:"type:varchar(50);not null;check:class IN ('warrior','paladin','hunter','rogue','priest','death-knight','shaman','mage','warlock','monk','druid','demon-hunter','evoker')" json:"class"`
+	Spec          string     `gorm:"type:varchar(50)" json:"spec"`
+	PreferredRole *string    `gorm:"type:varchar(20);check:preferred_role IN ('tank','healer','dps')" json:"preferred_role"` // Overrides the raid role of Spec when set
+	OffSpec       string     `gorm:"type:varchar(50)" json:"off_spec"`                                                       // Spec the character can switch to
+	Ilvl          int        `gorm:"not null" json:"ilvl"`
+	Level         int        `gorm:"not null;default:0" json:"level"`
+	GuildRank     *int       `json:"guild_rank"`                            // In-game guild rank, 0 being the guild master
+	LeftGuildAt   *time.Time `gorm:"type:timestamptz" json:"left_guild_at"` // Set when the character disappears from the in-game roster
+	LastSynced    time.Time  `gorm:"type:timestamptz" json:"last_synced"`
+	SyncError     string     `gorm:"type:text" json:"sync_error"` // Last sync failure, empty once a sync succeeds
+	UserID        *string    `gorm:"type:uuid" json:"user_id"`    // Nil for roster imports not yet claimed by a user
+	GuildID       string     `gorm:"type:uuid;not null" json:"guild_id"`
+	RaidGroupID   *string    `gorm:"type:uuid" json:"raid_group_id"` // Changed to pointer so that nil (NULL) is stored if not set
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
 
-	User          User           `gorm:"foreignKey:UserID"`
-	Guild         Guild          `gorm:"foreignKey:GuildID"`
-	RaidGroup     RaidGroup      `gorm:"foreignKey:RaidGroupID"`
-	Confirmations []Confirmation `gorm:"foreignKey:CharacterID"`
+	User          User           `gorm:"foreignKey:UserID" json:"-"`
+	Guild         Guild          `gorm:"foreignKey:GuildID" json:"-"`
+	RaidGroup     RaidGroup      `gorm:"foreignKey:RaidGroupID" json:"-"`
+	Confirmations []Confirmation `gorm:"foreignKey:CharacterID" json:"confirmations,omitempty"`
+}
+
+// Classes lists the values allowed by the characters.class CHECK constraint.
+var Classes = []string{
+	"warrior", "paladin", "hunter", "rogue", "priest",
+	"death-knight", "shaman", "mage", "warlock",
+	"monk", "druid", "demon-hunter", "evoker",
 }
@@ -5,13 +5,27 @@ import (
 )
 
 type Confirmation struct {
-	ID          string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
-	EventID     string    `gorm:"type:uuid;index"`
-	CharacterID string    `gorm:"type:uuid;index"`
-	Status      string    `gorm:"type:varchar(50);check:status IN ('confirmed','declined','tentative');default:'pending'"`
-	Reason      string    `gorm:"type:text"`
-	RespondedAt time.Time `gorm:"autoCreateTime"`
+	ID          string     `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	EventID     string     `gorm:"type:uuid;uniqueIndex:idx_confirmations_event_character" json:"event_id"`
+	CharacterID string     `gorm:"type:uuid;uniqueIndex:idx_confirmations_event_character" json:"character_id"`
+	Status      string     `gorm:"type:varchar(50);not null;check:status IN ('pending','confirmed','declined','tentative');default:'pending'" json:"status"`
+	Reason      string     `gorm:"type:text" json:"reason"`
+	RespondedAt *time.Time `gorm:"type:timestamptz" json:"responded_at"` // Nil until the member answers
+	Late        bool       `gorm:"not null;default:false" json:"late"`   // Answered or changed after the RSVP cutoff
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
 
-	Event     Event     `gorm:"foreignKey:EventID"`
-	Character Character `gorm:"foreignKey:CharacterID"`
+	Event     Event     `gorm:"foreignKey:EventID" json:"-"`
+	Character Character `gorm:"foreignKey:CharacterID" json:"-"`
 }
+
+// Confirmation statuses. Every confirmation starts out pending.
+const (
+	ConfirmationPending   = "pending"
+	ConfirmationConfirmed = "confirmed"
+	ConfirmationDeclined  = "declined"
+	ConfirmationTentative = "tentative"
+)
+
+// ConfirmationStatuses lists the answers a member can give; together with
+// ConfirmationPending they make up the confirmations.status CHECK constraint.
+var ConfirmationStatuses = []string{ConfirmationConfirmed, ConfirmationDeclined, ConfirmationTentative}
@@ -5,15 +5,25 @@ import (
 )
 
 type Event struct {
-	ID          string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
-	RaidName    string    `gorm:"type:varchar(255);not null"`
-	Difficulty  string    `gorm:"type:varchar(50);check:difficulty IN ('normal','heroic','mythic')"`
-	ScheduledAt time.Time `gorm:"type:timestamptz"`
-	CreatedBy   string    `gorm:"type:uuid;index"`
-	GuildID     string    `gorm:"type:uuid;index"`
-	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	ID          string     `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	RaidName    string     `gorm:"type:varchar(255);not null" json:"raid_name"`
+	Difficulty  string     `gorm:"type:varchar(50);check:difficulty IN ('normal','heroic','mythic')" json:"difficulty"`
+	ScheduledAt time.Time  `gorm:"type:timestamptz" json:"scheduled_at"`
+	EndsAt      *time.Time `gorm:"type:timestamptz" json:"ends_at"`
+	CreatedBy   *string    `gorm:"type:uuid;index" json:"created_by"` // nil for events generated from a schedule
+	GuildID     string     `gorm:"type:uuid;index" json:"guild_id"`
+	RaidGroupID *string    `gorm:"type:uuid;index" json:"raid_group_id"` // Raid group asked to respond, if any
+	// OccurrenceDate is the schedule date an event was generated for.
+	OccurrenceDate *time.Time `gorm:"type:date" json:"occurrence_date,omitempty"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+	// Sequence counts updates, as required by calendar clients.
+	Sequence int `gorm:"not null;default:0" json:"sequence"`
 
-	Creator       User           `gorm:"foreignKey:CreatedBy"`
-	Guild         Guild          `gorm:"foreignKey:GuildID"`
-	Confirmations []Confirmation `gorm:"foreignKey:EventID"`
+	Creator       User           `gorm:"foreignKey:CreatedBy" json:"-"`
+	Guild         Guild          `gorm:"foreignKey:GuildID" json:"-"`
+	Confirmations []Confirmation `gorm:"foreignKey:EventID" json:"confirmations,omitempty"`
 }
+
+// Difficulties lists the values allowed by the events.difficulty CHECK constraint.
+var Difficulties = []string{"normal", "heroic", "mythic"}
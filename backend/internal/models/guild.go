@@ -5,15 +5,20 @@ import (
 )
 
 type Guild struct {
-	ID        string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
-	Name      string    `gorm:"type:varchar(255);not null;uniqueIndex"`
-	Realm     string    `gorm:"type:varchar(255);not null"`
-	Faction   string    `gorm:"type:varchar(50);check:faction IN ('alliance','horde')"`
-	CreatedBy string    `gorm:"type:uuid;index"`
-	CreatedAt time.Time `gorm:"autoCreateTime"`
+	ID             string     `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	Name           string     `gorm:"type:varchar(255);not null;uniqueIndex:idx_guilds_realm_name,expression:lower(name)" json:"name"`
+	RealmID        string     `gorm:"type:uuid;not null;uniqueIndex:idx_guilds_realm_name" json:"realm_id"` // Realm IDs are per region
+	Realm          string     `gorm:"type:varchar(255);not null" json:"realm"`                              // Display name of RealmID
+	Faction        string     `gorm:"type:varchar(50);check:faction IN ('alliance','horde')" json:"faction"`
+	CreatedBy      string     `gorm:"type:uuid;index" json:"created_by"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	RosterSyncedAt *time.Time `gorm:"type:timestamptz" json:"roster_synced_at"`
 
-	Members    []User      `gorm:"many2many:guild_members;"`
-	Characters []Character `gorm:"foreignKey:GuildID"`
-	RaidGroups []RaidGroup `gorm:"foreignKey:GuildID"`
-	Events     []Event     `gorm:"foreignKey:GuildID"`
+	Members    []User      `gorm:"many2many:guild_members;" json:"members,omitempty"`
+	Characters []Character `gorm:"foreignKey:GuildID" json:"characters,omitempty"`
+	RaidGroups []RaidGroup `gorm:"foreignKey:GuildID" json:"raid_groups,omitempty"`
+	Events     []Event     `gorm:"foreignKey:GuildID" json:"events,omitempty"`
 }
+
+// Factions lists the values allowed by the guilds.faction CHECK constraint.
+var Factions = []string{"alliance", "horde"}
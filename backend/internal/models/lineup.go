@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+)
+
+// Lineup is the characters officers picked for an event among those who
+// answered. Every save bumps Version; once LockedAt is set, changes are
+// flagged in the audit trail and their characters' owners notified.
+type Lineup struct {
+	EventID   string       `gorm:"type:uuid;primaryKey" json:"event_id"`
+	Version   int          `gorm:"not null;default:1" json:"version"` // 0 until the lineup is first saved
+	LockedAt  *time.Time   `gorm:"type:timestamptz" json:"locked_at"`
+	LockedBy  *string      `gorm:"type:uuid" json:"locked_by"`
+	UpdatedBy *string      `gorm:"type:uuid" json:"updated_by"`
+	UpdatedAt time.Time    `gorm:"type:timestamptz;not null" json:"updated_at"`
+	Slots     []LineupSlot `gorm:"foreignKey:EventID;references:EventID" json:"slots"`
+}
+
+// LineupSlot places a character in a lineup.
+type LineupSlot struct {
+	EventID     string `gorm:"type:uuid;primaryKey" json:"-"`
+	CharacterID string `gorm:"type:uuid;primaryKey" json:"character_id"`
+	Status      string `gorm:"type:varchar(20);not null;check:status IN ('selected','standby','bench')" json:"status"`
+}
+
+// Lineup statuses. Selected characters raid; standby characters are ready
+// to replace them, benched ones are not expected to log in.
+const (
+	LineupSelected = "selected"
+	LineupStandby  = "standby"
+	LineupBench    = "bench"
+)
+
+// LineupStatuses lists the values allowed by the lineup_slots.status CHECK
+// constraint.
+var LineupStatuses = []string{LineupSelected, LineupStandby, LineupBench}
+
+// LineupCaps is how many characters may be selected per difficulty. Normal
+// and heroic scale from 10 to 30 players, mythic is fixed at 20.
+var LineupCaps = map[string]int{"normal": 30, "heroic": 30, "mythic": 20}
+
+// Lineup change actions.
+const (
+	LineupActionUpdate = "update"
+	LineupActionLock   = "lock"
+)
+
+// LineupChange is an entry of a lineup's audit trail: a character moving
+// between statuses, or the lineup being locked. An empty FromStatus means the
+// character was added, an empty ToStatus that it was removed.
+type LineupChange struct {
+	ID            string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	EventID       string    `gorm:"type:uuid;not null;index:idx_lineup_changes_event" json:"event_id"`
+	Version       int       `gorm:"not null" json:"version"` // Lineup version the change produced
+	Action        string    `gorm:"type:varchar(20);not null;check:action IN ('update','lock')" json:"action"`
+	CharacterID   *string   `gorm:"type:uuid" json:"character_id"` // Nil for locks and deleted characters
+	CharacterName string    `gorm:"type:varchar(255);not null;default:''" json:"character_name"`
+	FromStatus    string    `gorm:"type:varchar(20);not null;default:''" json:"from_status"`
+	ToStatus      string    `gorm:"type:varchar(20);not null;default:''" json:"to_status"`
+	AfterLock     bool      `gorm:"not null;default:false" json:"after_lock"`
+	ChangedBy     *string   `gorm:"type:uuid" json:"changed_by"`
+	ChangedAt     time.Time `gorm:"type:timestamptz;not null;index:idx_lineup_changes_event" json:"changed_at"`
+}
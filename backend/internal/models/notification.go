@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+// Notification is a message for a user, shown until they read it.
+type Notification struct {
+	ID        string     `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	UserID    string     `gorm:"type:uuid;not null;index:idx_notifications_user" json:"user_id"`
+	GuildID   *string    `gorm:"type:uuid" json:"guild_id"`
+	EventID   *string    `gorm:"type:uuid" json:"event_id"`
+	Kind      string     `gorm:"type:varchar(50);not null" json:"kind"`
+	Message   string     `gorm:"type:text;not null" json:"message"`
+	CreatedAt time.Time  `gorm:"type:timestamptz;not null;index:idx_notifications_user" json:"created_at"`
+	ReadAt    *time.Time `gorm:"type:timestamptz" json:"read_at"`
+}
+
+// Notification kinds.
+const (
+	// NotificationLineup tells a member where their character stands in a
+	// locked lineup.
+	NotificationLineup = "lineup"
+)
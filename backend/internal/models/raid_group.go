@@ -4,32 +4,117 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 )
 
-// Add this custom JSONB type at the top of the file
-type JSONB map[string]interface{}
+// Layouts used by RaidSchedule fields.
+const (
+	ScheduleDateLayout = "2006-01-02"
+	ScheduleTimeLayout = "15:04"
+)
+
+// Weekdays lists the day names accepted in RaidSchedule.Weekdays, indexed by
+// time.Weekday.
+var Weekdays = []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+// RaidSchedule describes when a raid group raids. Times are wall-clock times
+// in Timezone, so events keep their local start time across DST changes.
+type RaidSchedule struct {
+	Weekdays        []string `json:"weekdays"`
+	StartTime       string   `json:"start_time"`
+	DurationMinutes int      `json:"duration_minutes"`
+	Timezone        string   `json:"timezone"`
+	StartDate       string   `json:"start_date,omitempty"`
+	EndDate         string   `json:"end_date,omitempty"`
+	SkipDates       []string `json:"skip_dates,omitempty"`
+	// RaidName and Difficulty are copied to generated events; RaidName
+	// defaults to the group's name and Difficulty to normal.
+	RaidName   string `json:"raid_name,omitempty"`
+	Difficulty string `json:"difficulty,omitempty"`
+}
+
+// ScheduleError reports the first invalid field of a RaidSchedule.
+type ScheduleError struct {
+	Field   string
+	Message string
+}
+
+func (e *ScheduleError) Error() string {
+	return fmt.Sprintf("schedule.%s %s", e.Field, e.Message)
+}
+
+// Validate checks every field, returning a *ScheduleError for the first
+// invalid one.
+func (s *RaidSchedule) Validate() error {
+	if len(s.Weekdays) == 0 {
+		return &ScheduleError{"weekdays", "is required"}
+	}
+	for _, day := range s.Weekdays {
+		if !slices.Contains(Weekdays, strings.ToLower(day)) {
+			return &ScheduleError{"weekdays", "must only contain: " + strings.Join(Weekdays, ", ")}
+		}
+	}
+	if _, err := time.Parse(ScheduleTimeLayout, s.StartTime); err != nil {
+		return &ScheduleError{"start_time", "must be a HH:MM time"}
+	}
+	if s.DurationMinutes < 1 || s.DurationMinutes > 24*60 {
+		return &ScheduleError{"duration_minutes", "must be between 1 and 1440"}
+	}
+	if s.Timezone == "" {
+		return &ScheduleError{"timezone", "is required"}
+	}
+	if _, err := time.LoadLocation(s.Timezone); err != nil {
+		return &ScheduleError{"timezone", "must be an IANA time zone"}
+	}
 
-func (j JSONB) Value() (driver.Value, error) {
-	return json.Marshal(j)
+	var start, end time.Time
+	var err error
+	if s.StartDate != "" {
+		if start, err = time.Parse(ScheduleDateLayout, s.StartDate); err != nil {
+			return &ScheduleError{"start_date", "must be a YYYY-MM-DD date"}
+		}
+	}
+	if s.EndDate != "" {
+		if end, err = time.Parse(ScheduleDateLayout, s.EndDate); err != nil {
+			return &ScheduleError{"end_date", "must be a YYYY-MM-DD date"}
+		}
+		if !start.IsZero() && end.Before(start) {
+			return &ScheduleError{"end_date", "must not be before start_date"}
+		}
+	}
+	for _, date := range s.SkipDates {
+		if _, err := time.Parse(ScheduleDateLayout, date); err != nil {
+			return &ScheduleError{"skip_dates", "must only contain YYYY-MM-DD dates"}
+		}
+	}
+	if s.Difficulty != "" && !slices.Contains(Difficulties, s.Difficulty) {
+		return &ScheduleError{"difficulty", "must be one of: " + strings.Join(Difficulties, ", ")}
+	}
+	return nil
 }
 
-func (j *JSONB) Scan(value interface{}) error {
+func (s RaidSchedule) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+func (s *RaidSchedule) Scan(value interface{}) error {
 	b, ok := value.([]byte)
 	if !ok {
 		return errors.New("type assertion to []byte failed")
 	}
-	return json.Unmarshal(b, &j)
+	return json.Unmarshal(b, s)
 }
 
-// Update RaidGroup struct
 type RaidGroup struct {
-	ID        string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
-	Name      string    `gorm:"type:varchar(255);not null"`
-	GuildID   string    `gorm:"type:uuid;index"`
-	Schedule  JSONB     `gorm:"type:jsonb"` // Use custom type here
-	CreatedAt time.Time `gorm:"autoCreateTime"`
-
-	Guild      Guild       `gorm:"foreignKey:GuildID"`
-	Characters []Character `gorm:"many2many:raid_group_characters;"`
+	ID        string        `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	Name      string        `gorm:"type:varchar(255);not null" json:"name"`
+	GuildID   string        `gorm:"type:uuid;index" json:"guild_id"`
+	Schedule  *RaidSchedule `gorm:"type:jsonb" json:"schedule"` // nil when the group has no fixed raid days
+	CreatedAt time.Time     `gorm:"autoCreateTime" json:"created_at"`
+
+	Guild      Guild       `gorm:"foreignKey:GuildID" json:"-"`
+	Characters []Character `gorm:"many2many:raid_group_characters;" json:"characters,omitempty"`
 }
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// Realm is a game realm. Realms are per region, and realms sharing a
+// ConnectedRealmID form a connected-realm group whose characters can join the
+// same guilds.
+type Realm struct {
+	ID               string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	Region           string    `gorm:"type:varchar(10);not null;uniqueIndex:idx_realms_region_slug" json:"region"`
+	Slug             string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_realms_region_slug" json:"slug"`
+	Name             string    `gorm:"type:varchar(255);not null" json:"name"`
+	ConnectedRealmID *int64    `gorm:"index" json:"connected_realm_id"` // Nil until looked up on Battle.net
+	CreatedAt        time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// Regions lists the Battle.net regions realms can belong to.
+var Regions = []string{"us", "eu", "kr", "tw", "cn"}
@@ -4,21 +4,28 @@ import "time"
 
 // GuildMember represents the many-to-many relationship between users and guilds
 type GuildMember struct {
-	UserID   string `gorm:"type:uuid;primaryKey"`
-	GuildID  string `gorm:"type:uuid;primaryKey"`
-	JoinedAt time.Time
-	Role     string `gorm:"type:varchar(50)"`
+	UserID   string    `gorm:"type:uuid;primaryKey" json:"user_id"`
+	GuildID  string    `gorm:"type:uuid;primaryKey" json:"guild_id"`
+	JoinedAt time.Time `json:"joined_at"`
+	Role     string    `gorm:"type:varchar(50)" json:"role"`
 
-	User  User  `gorm:"foreignKey:UserID"`
-	Guild Guild `gorm:"foreignKey:GuildID"`
+	User  User  `gorm:"foreignKey:UserID" json:"-"`
+	Guild Guild `gorm:"foreignKey:GuildID" json:"-"`
 }
 
 // RaidGroupCharacter represents the many-to-many relationship between characters and raid groups
 type RaidGroupCharacter struct {
-	CharacterID string `gorm:"type:uuid;primaryKey"`
-	RaidGroupID string `gorm:"type:uuid;primaryKey"`
-	JoinedAt    time.Time
+	CharacterID string    `gorm:"type:uuid;primaryKey" json:"character_id"`
+	RaidGroupID string    `gorm:"type:uuid;primaryKey" json:"raid_group_id"`
+	JoinedAt    time.Time `json:"joined_at"`
 
-	Character Character `gorm:"foreignKey:CharacterID"`
-	RaidGroup RaidGroup `gorm:"foreignKey:RaidGroupID"`
+	Character Character `gorm:"foreignKey:CharacterID" json:"-"`
+	RaidGroup RaidGroup `gorm:"foreignKey:RaidGroupID" json:"-"`
 }
+
+// Guild roles stored in GuildMember.Role.
+const (
+	GuildRoleMember      = "member"
+	GuildRoleOfficer     = "officer"
+	GuildRoleGuildMaster = "guild-master"
+)
@@ -0,0 +1,94 @@
+package models
+
+import "strings"
+
+// Raid roles of a specialization.
+const (
+	RaidRoleTank   = "tank"
+	RaidRoleHealer = "healer"
+	RaidRoleDPS    = "dps"
+)
+
+// RaidRoles lists the raid roles in the order rosters show them.
+var RaidRoles = []string{RaidRoleTank, RaidRoleHealer, RaidRoleDPS}
+
+// SpecRoles maps every specialization of each class in Classes to its raid
+// role. Specs are keyed in lower case with dashes, like classes.
+var SpecRoles = map[string]map[string]string{
+	"warrior":      {"arms": RaidRoleDPS, "fury": RaidRoleDPS, "protection": RaidRoleTank},
+	"paladin":      {"holy": RaidRoleHealer, "protection": RaidRoleTank, "retribution": RaidRoleDPS},
+	"hunter":       {"beast-mastery": RaidRoleDPS, "marksmanship": RaidRoleDPS, "survival": RaidRoleDPS},
+	"rogue":        {"assassination": RaidRoleDPS, "outlaw": RaidRoleDPS, "subtlety": RaidRoleDPS},
+	"priest":       {"discipline": RaidRoleHealer, "holy": RaidRoleHealer, "shadow": RaidRoleDPS},
+	"death-knight": {"blood": RaidRoleTank, "frost": RaidRoleDPS, "unholy": RaidRoleDPS},
+	"shaman":       {"elemental": RaidRoleDPS, "enhancement": RaidRoleDPS, "restoration": RaidRoleHealer},
+	"mage":         {"arcane": RaidRoleDPS, "fire": RaidRoleDPS, "frost": RaidRoleDPS},
+	"warlock":      {"affliction": RaidRoleDPS, "demonology": RaidRoleDPS, "destruction": RaidRoleDPS},
+	"monk":         {"brewmaster": RaidRoleTank, "mistweaver": RaidRoleHealer, "windwalker": RaidRoleDPS},
+	"druid":        {"balance": RaidRoleDPS, "feral": RaidRoleDPS, "guardian": RaidRoleTank, "restoration": RaidRoleHealer},
+	"demon-hunter": {"havoc": RaidRoleDPS, "vengeance": RaidRoleTank},
+	"evoker":       {"augmentation": RaidRoleDPS, "devastation": RaidRoleDPS, "preservation": RaidRoleHealer},
+}
+
+// Armor types worn by each class.
+const (
+	ArmorCloth   = "cloth"
+	ArmorLeather = "leather"
+	ArmorMail    = "mail"
+	ArmorPlate   = "plate"
+)
+
+// ArmorTypes lists the armor types from lightest to heaviest.
+var ArmorTypes = []string{ArmorCloth, ArmorLeather, ArmorMail, ArmorPlate}
+
+// ClassArmor maps each class in Classes to the armor type it wears.
+var ClassArmor = map[string]string{
+	"warrior":      ArmorPlate,
+	"paladin":      ArmorPlate,
+	"hunter":       ArmorMail,
+	"rogue":        ArmorLeather,
+	"priest":       ArmorCloth,
+	"death-knight": ArmorPlate,
+	"shaman":       ArmorMail,
+	"mage":         ArmorCloth,
+	"warlock":      ArmorCloth,
+	"monk":         ArmorLeather,
+	"druid":        ArmorLeather,
+	"demon-hunter": ArmorLeather,
+	"evoker":       ArmorMail,
+}
+
+// SpecRole returns the raid role of a class's spec, accepting display names
+// such as "Beast Mastery". It returns "" for unknown or missing specs.
+func SpecRole(class, spec string) string {
+	key := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(spec)), " ", "-")
+	return SpecRoles[class][key]
+}
+
+// CanPlay reports whether some spec of the class fills role.
+func CanPlay(class, role string) bool {
+	for _, r := range SpecRoles[class] {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Role returns the character's preferred raid role, falling back to the role
+// of its current spec, or "" when neither is known.
+func (c *Character) Role() string {
+	if c.PreferredRole != nil {
+		return *c.PreferredRole
+	}
+	return SpecRole(c.Class, c.Spec)
+}
+
+// OffRole returns the raid role of the character's off-spec when it differs
+// from Role, or "".
+func (c *Character) OffRole() string {
+	if role := SpecRole(c.Class, c.OffSpec); role != c.Role() {
+		return role
+	}
+	return ""
+}
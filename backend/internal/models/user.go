@@ -5,15 +5,23 @@ import (
 )
 
 type User struct {
-	ID          string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
-	BattleNetID string    `gorm:"type:varchar(255);uniqueIndex;not null"`
-	Username    string    `gorm:"type:varchar(255);not null"`
-	Email       string    `gorm:"type:varchar(255);unique"`
-	Role        string    `gorm:"type:varchar(50);not null;default:'member'"`
-	CreatedAt   time.Time `gorm:"autoCreateTime"`
-	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+	ID          string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	BattleNetID string    `gorm:"type:varchar(255);uniqueIndex;not null" json:"battle_net_id"`
+	Username    string    `gorm:"type:varchar(255);not null" json:"username"`
+	Email       string    `gorm:"type:varchar(255);unique" json:"email"`
+	Role        string    `gorm:"type:varchar(50);not null;default:'member'" json:"role"`
+	FeedToken   *string   `gorm:"type:varchar(64);uniqueIndex" json:"-"` // Secret of the user's calendar feed URLs
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 
-	Characters    []Character `gorm:"foreignKey:UserID"`
-	CreatedGuilds []Guild     `gorm:"foreignKey:CreatedBy"`
-	Guilds        []Guild     `gorm:"many2many:guild_members;"`
+	Characters    []Character `gorm:"foreignKey:UserID" json:"characters,omitempty"`
+	CreatedGuilds []Guild     `gorm:"foreignKey:CreatedBy" json:"created_guilds,omitempty"`
+	Guilds        []Guild     `gorm:"many2many:guild_members;" json:"guilds,omitempty"`
 }
+
+// Site-wide roles stored in User.Role.
+const (
+	RoleMember  = "member"
+	RoleOfficer = "officer"
+	RoleAdmin   = "admin"
+)
@@ -0,0 +1,40 @@
+// Package realms keeps the realms table in step with the realms guilds and
+// characters are created on.
+package realms
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/pkg/blizzard"
+)
+
+// Resolve returns the realm called name in a region, creating it if needed.
+// Realms are matched by their Battle.net slug, so "Argent Dawn" and
+// "argent-dawn" are the same realm.
+func Resolve(tx *gorm.DB, region, name string) (*models.Realm, error) {
+	realm := models.Realm{
+		Region: strings.ToLower(region),
+		Slug:   blizzard.Slug(name),
+		Name:   strings.TrimSpace(name),
+	}
+	if realm.Slug == "" {
+		return nil, fmt.Errorf("realm name is empty")
+	}
+
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "region"}, {Name: "slug"}},
+		DoNothing: true,
+	}).Create(&realm).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to create realm %s: %w", realm.Slug, err)
+	}
+	if err := tx.First(&realm, "region = ? AND slug = ?", realm.Region, realm.Slug).Error; err != nil {
+		return nil, fmt.Errorf("failed to load realm %s: %w", realm.Slug, err)
+	}
+	return &realm, nil
+}
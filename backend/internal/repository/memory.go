@@ -0,0 +1,300 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/pkg/blizzard"
+)
+
+// memoryData holds the rows of a memoryStore. Join tables are keyed by
+// their two IDs.
+type memoryData struct {
+	users           map[string]models.User
+	realms          map[string]models.Realm
+	guilds          map[string]models.Guild
+	members         map[[2]string]models.GuildMember // guild ID, user ID
+	characters      map[string]models.Character
+	raidGroups      map[string]models.RaidGroup
+	groupCharacters map[[2]string]time.Time // raid group ID, character ID
+	events          map[string]models.Event
+	confirmations   map[string]models.Confirmation
+	attendances     map[[2]string]models.Attendance // event ID, character ID
+	lineups         map[string]models.Lineup        // event ID
+	lineupChanges   map[string]models.LineupChange
+	notifications   map[string]models.Notification
+}
+
+func (d *memoryData) clone() *memoryData {
+	return &memoryData{
+		users:           maps.Clone(d.users),
+		realms:          maps.Clone(d.realms),
+		guilds:          maps.Clone(d.guilds),
+		members:         maps.Clone(d.members),
+		characters:      maps.Clone(d.characters),
+		raidGroups:      maps.Clone(d.raidGroups),
+		groupCharacters: maps.Clone(d.groupCharacters),
+		events:          maps.Clone(d.events),
+		confirmations:   maps.Clone(d.confirmations),
+		attendances:     maps.Clone(d.attendances),
+		lineups:         maps.Clone(d.lineups),
+		lineupChanges:   maps.Clone(d.lineupChanges),
+		notifications:   maps.Clone(d.notifications),
+	}
+}
+
+// memoryStore implements Store with maps, emulating the unique and foreign
+// key constraints and cascades the handlers rely on. A transaction holds
+// the store's lock and restores a snapshot when it fails.
+type memoryStore struct {
+	mu   *sync.Mutex
+	data **memoryData
+	inTx bool
+	now  func() time.Time
+}
+
+// NewMemoryStore returns an empty in-memory Store for tests.
+func NewMemoryStore() Store {
+	data := &memoryData{
+		users:           map[string]models.User{},
+		realms:          map[string]models.Realm{},
+		guilds:          map[string]models.Guild{},
+		members:         map[[2]string]models.GuildMember{},
+		characters:      map[string]models.Character{},
+		raidGroups:      map[string]models.RaidGroup{},
+		groupCharacters: map[[2]string]time.Time{},
+		events:          map[string]models.Event{},
+		confirmations:   map[string]models.Confirmation{},
+		attendances:     map[[2]string]models.Attendance{},
+		lineups:         map[string]models.Lineup{},
+		lineupChanges:   map[string]models.LineupChange{},
+		notifications:   map[string]models.Notification{},
+	}
+	return &memoryStore{
+		mu:   &sync.Mutex{},
+		data: &data,
+		now:  func() time.Time { return time.Now().UTC() },
+	}
+}
+
+func (s *memoryStore) Users() UserRepository                 { return memoryUsers{s} }
+func (s *memoryStore) Realms() RealmRepository               { return memoryRealms{s} }
+func (s *memoryStore) Guilds() GuildRepository               { return memoryGuilds{s} }
+func (s *memoryStore) Characters() CharacterRepository       { return memoryCharacters{s} }
+func (s *memoryStore) RaidGroups() RaidGroupRepository       { return memoryRaidGroups{s} }
+func (s *memoryStore) Events() EventRepository               { return memoryEvents{s} }
+func (s *memoryStore) Confirmations() ConfirmationRepository { return memoryConfirmations{s} }
+func (s *memoryStore) Attendances() AttendanceRepository     { return memoryAttendances{s} }
+func (s *memoryStore) Lineups() LineupRepository             { return memoryLineups{s} }
+func (s *memoryStore) Notifications() NotificationRepository { return memoryNotifications{s} }
+
+func (s *memoryStore) Transaction(_ context.Context, fn func(tx Store) error) error {
+	if s.inTx {
+		return fn(s)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snapshot := (*s.data).clone()
+	if err := fn(&memoryStore{mu: s.mu, data: s.data, inTx: true, now: s.now}); err != nil {
+		*s.data = snapshot
+		return err
+	}
+	return nil
+}
+
+// lock takes the store's lock unless a transaction already holds it, and
+// returns the data with the function releasing the lock.
+func (s *memoryStore) lock() (*memoryData, func()) {
+	if s.inTx {
+		return *s.data, func() {}
+	}
+	s.mu.Lock()
+	return *s.data, s.mu.Unlock
+}
+
+// newID returns a random (version 4) UUID like uuid_generate_v4().
+func newID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// page applies a Page to a sorted list.
+func page[T any](list []T, p Page) []T {
+	if p.Offset >= len(list) {
+		return []T{}
+	}
+	list = list[p.Offset:]
+	if p.Limit > 0 && p.Limit < len(list) {
+		list = list[:p.Limit]
+	}
+	return list
+}
+
+// values returns the rows of m that match keep, sorted with less.
+func values[K comparable, T any](m map[K]T, keep func(T) bool, less func(a, b T) bool) []T {
+	list := []T{}
+	for _, v := range m {
+		if keep == nil || keep(v) {
+			list = append(list, v)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return less(list[i], list[j]) })
+	return list
+}
+
+type memoryUsers struct {
+	s *memoryStore
+}
+
+func (r memoryUsers) Get(_ context.Context, id string) (*models.User, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	user, ok := data.users[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &user, nil
+}
+
+func (r memoryUsers) GetByFeedToken(_ context.Context, token string) (*models.User, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	for _, user := range data.users {
+		if user.FeedToken != nil && *user.FeedToken == token {
+			return &user, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r memoryUsers) Create(_ context.Context, user *models.User) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	return r.create(data, user)
+}
+
+func (r memoryUsers) create(data *memoryData, user *models.User) error {
+	for _, u := range data.users {
+		if u.BattleNetID == user.BattleNetID || (user.Email != "" && u.Email == user.Email) {
+			return fmt.Errorf("user %s: %w", user.BattleNetID, ErrConflict)
+		}
+	}
+	if user.ID == "" {
+		user.ID = newID()
+	}
+	if user.Role == "" {
+		user.Role = models.RoleMember
+	}
+	user.CreatedAt, user.UpdatedAt = r.s.now(), r.s.now()
+	data.users[user.ID] = *user
+	return nil
+}
+
+func (r memoryUsers) Upsert(_ context.Context, user *models.User) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	for id, u := range data.users {
+		if u.BattleNetID == user.BattleNetID {
+			u.Username, u.UpdatedAt = user.Username, r.s.now()
+			data.users[id] = u
+			user.ID = id
+			return nil
+		}
+	}
+	return r.create(data, user)
+}
+
+func (r memoryUsers) SetFeedToken(_ context.Context, id, token string) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	user, ok := data.users[id]
+	if !ok {
+		return ErrNotFound
+	}
+	user.FeedToken = &token
+	user.UpdatedAt = r.s.now()
+	data.users[id] = user
+	return nil
+}
+
+type memoryRealms struct {
+	s *memoryStore
+}
+
+func (r memoryRealms) List(_ context.Context, region string, p Page) ([]models.Realm, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	list := values(data.realms,
+		func(realm models.Realm) bool { return region == "" || realm.Region == region },
+		func(a, b models.Realm) bool {
+			if a.Region != b.Region {
+				return a.Region < b.Region
+			}
+			return a.Name < b.Name
+		})
+	return page(list, p), nil
+}
+
+func (r memoryRealms) Get(_ context.Context, id string) (*models.Realm, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	realm, ok := data.realms[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &realm, nil
+}
+
+func (r memoryRealms) Resolve(_ context.Context, region, name string) (*models.Realm, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	realm := models.Realm{
+		Region: strings.ToLower(region),
+		Slug:   blizzard.Slug(name),
+		Name:   strings.TrimSpace(name),
+	}
+	if realm.Slug == "" {
+		return nil, fmt.Errorf("realm name is empty")
+	}
+	for _, existing := range data.realms {
+		if existing.Region == realm.Region && existing.Slug == realm.Slug {
+			return &existing, nil
+		}
+	}
+	realm.ID = newID()
+	realm.CreatedAt = r.s.now()
+	data.realms[realm.ID] = realm
+	return &realm, nil
+}
+
+func (r memoryRealms) SetConnectedRealm(_ context.Context, id string, connectedRealmID int64) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	if realm, ok := data.realms[id]; ok {
+		realm.ConnectedRealmID = &connectedRealmID
+		data.realms[id] = realm
+	}
+	return nil
+}
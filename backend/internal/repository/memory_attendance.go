@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+)
+
+type memoryAttendances struct {
+	s *memoryStore
+}
+
+func (r memoryAttendances) List(_ context.Context, eventID string) ([]models.Attendance, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	return values(data.attendances,
+		func(a models.Attendance) bool { return a.EventID == eventID },
+		func(a, b models.Attendance) bool { return a.CharacterID < b.CharacterID }), nil
+}
+
+func (r memoryAttendances) Save(_ context.Context, records []models.Attendance) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	for _, record := range records {
+		if _, ok := data.events[record.EventID]; !ok {
+			return fmt.Errorf("attendance references a missing event: %w", ErrConflict)
+		}
+		if _, ok := data.characters[record.CharacterID]; !ok {
+			return fmt.Errorf("attendance references a missing character: %w", ErrConflict)
+		}
+	}
+	for i := range records {
+		key := [2]string{records[i].EventID, records[i].CharacterID}
+		if stored, ok := data.attendances[key]; ok {
+			records[i].ID = stored.ID
+		} else {
+			records[i].ID = newID()
+		}
+		data.attendances[key] = records[i]
+	}
+	return nil
+}
+
+func (r memoryAttendances) Delete(_ context.Context, eventID, characterID string) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	key := [2]string{eventID, characterID}
+	if _, ok := data.attendances[key]; !ok {
+		return ErrNotFound
+	}
+	delete(data.attendances, key)
+	return nil
+}
+
+func (r memoryAttendances) Counts(_ context.Context, f AttendanceFilter) ([]AttendanceCounts, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	byCharacter := map[string]*AttendanceCounts{}
+	for _, a := range data.attendances {
+		event := data.events[a.EventID]
+		if event.GuildID != f.GuildID || event.ScheduledAt.Before(f.From) || !event.ScheduledAt.Before(f.To) {
+			continue
+		}
+		if f.RaidGroupID != "" && (event.RaidGroupID == nil || *event.RaidGroupID != f.RaidGroupID) {
+			continue
+		}
+
+		counts, ok := byCharacter[a.CharacterID]
+		if !ok {
+			character := data.characters[a.CharacterID]
+			counts = &AttendanceCounts{CharacterID: character.ID, Name: character.Name, Realm: character.Realm}
+			byCharacter[a.CharacterID] = counts
+		}
+		counts.Events++
+		switch a.Status {
+		case models.AttendancePresent:
+			counts.Present++
+		case models.AttendanceLate:
+			counts.Late++
+		case models.AttendanceAbsent:
+			counts.Absent++
+		case models.AttendanceBenched:
+			counts.Benched++
+		case models.AttendanceExcused:
+			counts.Excused++
+		}
+
+		for _, c := range data.confirmations {
+			if c.EventID != a.EventID || c.CharacterID != a.CharacterID {
+				continue
+			}
+			if a.Status == models.AttendanceAbsent && c.Status == models.ConfirmationConfirmed {
+				counts.NoShows++
+			}
+			if c.Status == models.ConfirmationDeclined {
+				counts.Declined++
+			}
+		}
+	}
+
+	list := values(byCharacter, nil, func(a, b *AttendanceCounts) bool {
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Realm < b.Realm
+	})
+	counts := make([]AttendanceCounts, len(list))
+	for i, c := range list {
+		counts[i] = *c
+	}
+	return counts, nil
+}
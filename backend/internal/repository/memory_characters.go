@@ -0,0 +1,364 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+)
+
+type memoryCharacters struct {
+	s *memoryStore
+}
+
+func (r memoryCharacters) List(_ context.Context, filter CharacterFilter) ([]models.Character, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	list := values(data.characters,
+		func(c models.Character) bool {
+			return c.GuildID == filter.GuildID &&
+				(filter.IncludeLeft || c.LeftGuildAt == nil) &&
+				(filter.Class == "" || c.Class == filter.Class)
+		},
+		func(a, b models.Character) bool { return a.Name < b.Name })
+	return page(list, filter.Page), nil
+}
+
+func (r memoryCharacters) Get(_ context.Context, guildID, id string) (*models.Character, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	character, ok := data.characters[id]
+	if !ok || character.GuildID != guildID {
+		return nil, ErrNotFound
+	}
+	return &character, nil
+}
+
+func (r memoryCharacters) GetMany(_ context.Context, guildID string, ids []string) ([]models.Character, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	return values(data.characters,
+		func(c models.Character) bool { return c.GuildID == guildID && slices.Contains(ids, c.ID) },
+		func(a, b models.Character) bool { return a.Name < b.Name }), nil
+}
+
+func (r memoryCharacters) Create(_ context.Context, character *models.Character) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	if err := data.checkCharacter(character); err != nil {
+		return err
+	}
+	character.ID = newID()
+	character.CreatedAt, character.UpdatedAt = r.s.now(), r.s.now()
+	data.characters[character.ID] = *character
+	return nil
+}
+
+func (r memoryCharacters) Update(_ context.Context, character *models.Character) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	stored, ok := data.characters[character.ID]
+	if !ok {
+		return nil
+	}
+	if err := data.checkCharacter(character); err != nil {
+		return err
+	}
+	stored.Name, stored.RealmID, stored.Realm = character.Name, character.RealmID, character.Realm
+	stored.Class, stored.Spec, stored.Ilvl, stored.Level = character.Class, character.Spec, character.Ilvl, character.Level
+	stored.PreferredRole, stored.OffSpec = character.PreferredRole, character.OffSpec
+	stored.UserID, stored.RaidGroupID = character.UserID, character.RaidGroupID
+	stored.UpdatedAt = r.s.now()
+	data.characters[character.ID] = stored
+	*character = stored
+	return nil
+}
+
+func (r memoryCharacters) Delete(_ context.Context, character *models.Character) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	data.deleteCharacter(character.ID)
+	return nil
+}
+
+func (r memoryCharacters) FindByName(_ context.Context, realmID, name string) (*models.Character, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	for _, c := range data.characters {
+		if c.RealmID == realmID && strings.ToLower(c.Name) == strings.ToLower(name) {
+			return &c, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r memoryCharacters) GetByIDs(_ context.Context, ids []string) ([]models.Character, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	return values(data.characters,
+		func(c models.Character) bool { return slices.Contains(ids, c.ID) },
+		func(a, b models.Character) bool { return a.Name < b.Name }), nil
+}
+
+func (r memoryCharacters) SaveRoster(_ context.Context, character *models.Character) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	stored, ok := data.characters[character.ID]
+	if !ok {
+		return nil
+	}
+	if _, ok := data.guilds[character.GuildID]; !ok {
+		return fmt.Errorf("character references a missing guild: %w", ErrConflict)
+	}
+	stored.GuildID, stored.Class, stored.Level = character.GuildID, character.Class, character.Level
+	stored.GuildRank, stored.LeftGuildAt = character.GuildRank, character.LeftGuildAt
+	stored.UpdatedAt = r.s.now()
+	data.characters[character.ID] = stored
+	return nil
+}
+
+func (r memoryCharacters) ListForSync(_ context.Context, filter SyncFilter) ([]string, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	list := values(data.characters,
+		func(c models.Character) bool {
+			return c.LeftGuildAt == nil &&
+				(filter.GuildID == "" || c.GuildID == filter.GuildID) &&
+				(filter.SyncedBefore.IsZero() || c.LastSynced.Before(filter.SyncedBefore))
+		},
+		func(a, b models.Character) bool { return a.LastSynced.Before(b.LastSynced) })
+	ids := make([]string, len(list))
+	for i, c := range list {
+		ids[i] = c.ID
+	}
+	return ids, nil
+}
+
+func (r memoryCharacters) SaveSync(_ context.Context, character *models.Character) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	stored, ok := data.characters[character.ID]
+	if !ok {
+		return nil
+	}
+	stored.Class, stored.Spec, stored.Ilvl = character.Class, character.Spec, character.Ilvl
+	stored.LastSynced, stored.SyncError = character.LastSynced, character.SyncError
+	stored.UpdatedAt = r.s.now()
+	data.characters[character.ID] = stored
+	return nil
+}
+
+func (r memoryCharacters) SetSyncError(_ context.Context, id, message string) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	if character, ok := data.characters[id]; ok {
+		character.SyncError = message
+		data.characters[id] = character
+	}
+	return nil
+}
+
+// checkCharacter enforces the unique realm and case-insensitive name of characters and their
+// references to a user and guild.
+func (d *memoryData) checkCharacter(character *models.Character) error {
+	for _, c := range d.characters {
+		if c.ID != character.ID && c.RealmID == character.RealmID && strings.ToLower(c.Name) == strings.ToLower(character.Name) {
+			return fmt.Errorf("character %s: %w", character.Name, ErrConflict)
+		}
+	}
+	if _, ok := d.guilds[character.GuildID]; !ok {
+		return fmt.Errorf("character references a missing guild: %w", ErrConflict)
+	}
+	if character.UserID != nil {
+		if _, ok := d.users[*character.UserID]; !ok {
+			return fmt.Errorf("character references a missing user: %w", ErrConflict)
+		}
+	}
+	return nil
+}
+
+// deleteCharacter removes a character with its confirmations, attendance,
+// raid group memberships and lineup slots. The audit trail keeps its name.
+func (d *memoryData) deleteCharacter(id string) {
+	delete(d.characters, id)
+	for key := range d.groupCharacters {
+		if key[1] == id {
+			delete(d.groupCharacters, key)
+		}
+	}
+	for key := range d.attendances {
+		if key[1] == id {
+			delete(d.attendances, key)
+		}
+	}
+	for _, c := range d.confirmations {
+		if c.CharacterID == id {
+			delete(d.confirmations, c.ID)
+		}
+	}
+	for eventID, l := range d.lineups {
+		l.Slots = slices.DeleteFunc(slices.Clone(l.Slots), func(s models.LineupSlot) bool { return s.CharacterID == id })
+		d.lineups[eventID] = l
+	}
+	for _, c := range d.lineupChanges {
+		if c.CharacterID != nil && *c.CharacterID == id {
+			c.CharacterID = nil
+			d.lineupChanges[c.ID] = c
+		}
+	}
+}
+
+type memoryRaidGroups struct {
+	s *memoryStore
+}
+
+func (r memoryRaidGroups) List(_ context.Context, guildID string, p Page) ([]models.RaidGroup, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	list := values(data.raidGroups,
+		func(g models.RaidGroup) bool { return g.GuildID == guildID },
+		func(a, b models.RaidGroup) bool { return a.Name < b.Name })
+	for i := range list {
+		list[i].Schedule = cloneSchedule(list[i].Schedule)
+	}
+	return page(list, p), nil
+}
+
+func (r memoryRaidGroups) ListScheduled(_ context.Context) ([]models.RaidGroup, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	list := values(data.raidGroups,
+		func(g models.RaidGroup) bool { return g.Schedule != nil },
+		func(a, b models.RaidGroup) bool { return a.ID < b.ID })
+	for i := range list {
+		list[i].Schedule = cloneSchedule(list[i].Schedule)
+	}
+	return list, nil
+}
+
+func (r memoryRaidGroups) Get(_ context.Context, guildID, id string, withCharacters bool) (*models.RaidGroup, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	group, ok := data.raidGroups[id]
+	if !ok || group.GuildID != guildID {
+		return nil, ErrNotFound
+	}
+	group.Schedule = cloneSchedule(group.Schedule)
+	if withCharacters {
+		group.Characters = values(data.characters,
+			func(c models.Character) bool { _, ok := data.groupCharacters[[2]string{id, c.ID}]; return ok },
+			func(a, b models.Character) bool { return a.Name < b.Name })
+	}
+	return &group, nil
+}
+
+func (r memoryRaidGroups) Create(_ context.Context, group *models.RaidGroup) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	if _, ok := data.guilds[group.GuildID]; !ok {
+		return fmt.Errorf("raid group references a missing guild: %w", ErrConflict)
+	}
+	group.ID = newID()
+	group.CreatedAt = r.s.now()
+	stored := *group
+	stored.Schedule = cloneSchedule(group.Schedule)
+	stored.Characters = nil
+	data.raidGroups[group.ID] = stored
+	return nil
+}
+
+func (r memoryRaidGroups) Update(_ context.Context, group *models.RaidGroup) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	stored, ok := data.raidGroups[group.ID]
+	if !ok {
+		return nil
+	}
+	stored.Name, stored.Schedule = group.Name, cloneSchedule(group.Schedule)
+	data.raidGroups[group.ID] = stored
+	return nil
+}
+
+func (r memoryRaidGroups) Delete(_ context.Context, group *models.RaidGroup) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	data.deleteRaidGroup(group.ID)
+	return nil
+}
+
+func (r memoryRaidGroups) SetCharacters(_ context.Context, group *models.RaidGroup, characters []models.Character) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	for _, c := range characters {
+		if _, ok := data.characters[c.ID]; !ok {
+			return fmt.Errorf("raid group references a missing character: %w", ErrConflict)
+		}
+	}
+	kept := map[string]bool{}
+	for _, c := range characters {
+		kept[c.ID] = true
+	}
+	for key := range data.groupCharacters {
+		if key[0] == group.ID && !kept[key[1]] {
+			delete(data.groupCharacters, key)
+		}
+	}
+	for id := range kept {
+		key := [2]string{group.ID, id}
+		if _, ok := data.groupCharacters[key]; !ok {
+			data.groupCharacters[key] = r.s.now()
+		}
+	}
+	group.Characters = characters
+	return nil
+}
+
+// deleteRaidGroup removes a raid group and its memberships, detaching its
+// events like ON DELETE SET NULL.
+func (d *memoryData) deleteRaidGroup(id string) {
+	delete(d.raidGroups, id)
+	for key := range d.groupCharacters {
+		if key[0] == id {
+			delete(d.groupCharacters, key)
+		}
+	}
+	for _, e := range d.events {
+		if e.RaidGroupID != nil && *e.RaidGroupID == id {
+			e.RaidGroupID = nil
+			d.events[e.ID] = e
+		}
+	}
+}
+
+// cloneSchedule copies a schedule so callers cannot change stored rows, and
+// rolled back transactions leave none behind.
+func cloneSchedule(s *models.RaidSchedule) *models.RaidSchedule {
+	if s == nil {
+		return nil
+	}
+	clone := *s
+	clone.Weekdays = slices.Clone(s.Weekdays)
+	clone.SkipDates = slices.Clone(s.SkipDates)
+	return &clone
+}
@@ -0,0 +1,308 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+)
+
+type memoryEvents struct {
+	s *memoryStore
+}
+
+func (r memoryEvents) List(_ context.Context, filter EventFilter) ([]models.Event, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	list := values(data.events,
+		func(e models.Event) bool {
+			return (filter.GuildID == "" || e.GuildID == filter.GuildID) &&
+				(filter.UserID == "" || data.hasUserConfirmation(filter.UserID, e.ID)) &&
+				(filter.From.IsZero() || !e.ScheduledAt.Before(filter.From)) &&
+				(filter.To.IsZero() || e.ScheduledAt.Before(filter.To))
+		},
+		func(a, b models.Event) bool { return a.ScheduledAt.Before(b.ScheduledAt) })
+	return page(list, filter.Page), nil
+}
+
+func (r memoryEvents) Get(_ context.Context, guildID, id string) (*models.Event, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	event, ok := data.events[id]
+	if !ok || event.GuildID != guildID {
+		return nil, ErrNotFound
+	}
+	return &event, nil
+}
+
+func (r memoryEvents) Create(_ context.Context, event *models.Event) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	return r.create(data, event)
+}
+
+func (r memoryEvents) create(data *memoryData, event *models.Event) error {
+	if _, ok := data.guilds[event.GuildID]; !ok {
+		return fmt.Errorf("event references a missing guild: %w", ErrConflict)
+	}
+	if data.findOccurrence(event) != "" {
+		return fmt.Errorf("event occurrence: %w", ErrConflict)
+	}
+	event.ID = newID()
+	event.CreatedAt, event.UpdatedAt = r.s.now(), r.s.now()
+	stored := *event
+	stored.Confirmations = nil
+	data.events[event.ID] = stored
+	return nil
+}
+
+func (r memoryEvents) Update(_ context.Context, event *models.Event) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	stored, ok := data.events[event.ID]
+	if !ok {
+		return nil
+	}
+	stored.RaidName, stored.Difficulty = event.RaidName, event.Difficulty
+	stored.ScheduledAt, stored.EndsAt, stored.RaidGroupID = event.ScheduledAt, event.EndsAt, event.RaidGroupID
+	stored.Sequence++
+	stored.UpdatedAt = r.s.now()
+	data.events[event.ID] = stored
+	event.Sequence, event.UpdatedAt = stored.Sequence, stored.UpdatedAt
+	return nil
+}
+
+func (r memoryEvents) Delete(_ context.Context, event *models.Event) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	data.deleteEvent(event.ID)
+	return nil
+}
+
+func (r memoryEvents) CreateOccurrence(_ context.Context, event *models.Event) (bool, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	if event.RaidGroupID == nil || event.OccurrenceDate == nil {
+		return false, fmt.Errorf("scheduled events need a raid group and an occurrence date")
+	}
+	if data.findOccurrence(event) != "" {
+		return false, nil
+	}
+	if err := r.create(data, event); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// findOccurrence returns the ID of the raid group's event on the event's
+// occurrence date, enforcing the unique index of generated events.
+func (d *memoryData) findOccurrence(event *models.Event) string {
+	if event.RaidGroupID == nil || event.OccurrenceDate == nil {
+		return ""
+	}
+	date := event.OccurrenceDate.Format(models.ScheduleDateLayout)
+	for _, e := range d.events {
+		if e.RaidGroupID != nil && *e.RaidGroupID == *event.RaidGroupID &&
+			e.OccurrenceDate != nil && e.OccurrenceDate.Format(models.ScheduleDateLayout) == date {
+			return e.ID
+		}
+	}
+	return ""
+}
+
+// deleteEvent removes an event with its confirmations, attendance, lineup
+// and notifications.
+func (d *memoryData) deleteEvent(id string) {
+	delete(d.events, id)
+	for _, c := range d.confirmations {
+		if c.EventID == id {
+			delete(d.confirmations, c.ID)
+		}
+	}
+	for key := range d.attendances {
+		if key[0] == id {
+			delete(d.attendances, key)
+		}
+	}
+	delete(d.lineups, id)
+	for _, c := range d.lineupChanges {
+		if c.EventID == id {
+			delete(d.lineupChanges, c.ID)
+		}
+	}
+	for _, n := range d.notifications {
+		if n.EventID != nil && *n.EventID == id {
+			delete(d.notifications, n.ID)
+		}
+	}
+}
+
+func (d *memoryData) hasUserConfirmation(userID, eventID string) bool {
+	for _, c := range d.confirmations {
+		if c.EventID != eventID {
+			continue
+		}
+		if owner := d.characters[c.CharacterID].UserID; owner != nil && *owner == userID {
+			return true
+		}
+	}
+	return false
+}
+
+type memoryConfirmations struct {
+	s *memoryStore
+}
+
+func (r memoryConfirmations) List(_ context.Context, eventID, status string) ([]models.Confirmation, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	return values(data.confirmations,
+		func(c models.Confirmation) bool { return c.EventID == eventID && (status == "" || c.Status == status) },
+		func(a, b models.Confirmation) bool { return a.CreatedAt.Before(b.CreatedAt) }), nil
+}
+
+func (r memoryConfirmations) Get(_ context.Context, eventID, id string) (*models.Confirmation, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	confirmation, ok := data.confirmations[id]
+	if !ok || confirmation.EventID != eventID {
+		return nil, ErrNotFound
+	}
+	return &confirmation, nil
+}
+
+func (r memoryConfirmations) Find(_ context.Context, eventID, characterID string) (*models.Confirmation, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	if id := data.findConfirmation(eventID, characterID); id != "" {
+		confirmation := data.confirmations[id]
+		return &confirmation, nil
+	}
+	return nil, ErrNotFound
+}
+
+func (r memoryConfirmations) Create(_ context.Context, confirmation *models.Confirmation) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	return r.create(data, confirmation)
+}
+
+func (r memoryConfirmations) create(data *memoryData, confirmation *models.Confirmation) error {
+	if _, ok := data.events[confirmation.EventID]; !ok {
+		return fmt.Errorf("confirmation references a missing event: %w", ErrConflict)
+	}
+	if _, ok := data.characters[confirmation.CharacterID]; !ok {
+		return fmt.Errorf("confirmation references a missing character: %w", ErrConflict)
+	}
+	if data.findConfirmation(confirmation.EventID, confirmation.CharacterID) != "" {
+		return fmt.Errorf("confirmation: %w", ErrConflict)
+	}
+	if confirmation.Status == "" {
+		confirmation.Status = models.ConfirmationPending
+	}
+	confirmation.ID = newID()
+	confirmation.CreatedAt = r.s.now()
+	data.confirmations[confirmation.ID] = *confirmation
+	return nil
+}
+
+func (r memoryConfirmations) Update(_ context.Context, confirmation *models.Confirmation) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	stored, ok := data.confirmations[confirmation.ID]
+	if !ok {
+		return nil
+	}
+	stored.Status, stored.Reason = confirmation.Status, confirmation.Reason
+	stored.RespondedAt, stored.Late = confirmation.RespondedAt, confirmation.Late
+	data.confirmations[confirmation.ID] = stored
+	return nil
+}
+
+func (r memoryConfirmations) Delete(_ context.Context, confirmation *models.Confirmation) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	delete(data.confirmations, confirmation.ID)
+	return nil
+}
+
+func (r memoryConfirmations) CreatePending(_ context.Context, event *models.Event) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	return r.createPending(data, event)
+}
+
+func (r memoryConfirmations) createPending(data *memoryData, event *models.Event) error {
+	if event.RaidGroupID == nil {
+		return nil
+	}
+	for key := range data.groupCharacters {
+		if key[0] != *event.RaidGroupID || data.characters[key[1]].LeftGuildAt != nil {
+			continue
+		}
+		if data.findConfirmation(event.ID, key[1]) != "" {
+			continue
+		}
+		pending := models.Confirmation{EventID: event.ID, CharacterID: key[1], Status: models.ConfirmationPending}
+		if err := r.create(data, &pending); err != nil {
+			return fmt.Errorf("failed to create pending confirmations: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r memoryConfirmations) CreatePendingForGroup(_ context.Context, raidGroupID string, now time.Time) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	for _, e := range data.events {
+		if e.RaidGroupID == nil || *e.RaidGroupID != raidGroupID || !e.ScheduledAt.After(now) {
+			continue
+		}
+		if err := r.createPending(data, &e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r memoryConfirmations) ForUser(_ context.Context, userID string, eventIDs []string) ([]CharacterRSVP, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	var rsvps []CharacterRSVP
+	for _, c := range data.confirmations {
+		character := data.characters[c.CharacterID]
+		if !slices.Contains(eventIDs, c.EventID) || character.UserID == nil || *character.UserID != userID {
+			continue
+		}
+		rsvps = append(rsvps, CharacterRSVP{EventID: c.EventID, CharacterName: character.Name, Status: c.Status})
+	}
+	slices.SortFunc(rsvps, func(a, b CharacterRSVP) int { return strings.Compare(a.CharacterName, b.CharacterName) })
+	return rsvps, nil
+}
+
+func (d *memoryData) findConfirmation(eventID, characterID string) string {
+	for _, c := range d.confirmations {
+		if c.EventID == eventID && c.CharacterID == characterID {
+			return c.ID
+		}
+	}
+	return ""
+}
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+)
+
+type memoryGuilds struct {
+	s *memoryStore
+}
+
+func (r memoryGuilds) List(_ context.Context, p Page) ([]models.Guild, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	list := values(data.guilds, nil, func(a, b models.Guild) bool { return a.Name < b.Name })
+	return page(list, p), nil
+}
+
+func (r memoryGuilds) ListForUser(_ context.Context, userID string) ([]models.Guild, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	return values(data.guilds,
+		func(g models.Guild) bool {
+			_, ok := data.members[[2]string{g.ID, userID}]
+			return ok
+		},
+		func(a, b models.Guild) bool { return a.Name < b.Name }), nil
+}
+
+func (r memoryGuilds) Get(_ context.Context, id string) (*models.Guild, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	guild, ok := data.guilds[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &guild, nil
+}
+
+func (r memoryGuilds) Find(_ context.Context, region, realmSlug, name string) (*models.Guild, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	for _, guild := range data.guilds {
+		realm := data.realms[guild.RealmID]
+		if realm.Region == region && realm.Slug == realmSlug && strings.ToLower(guild.Name) == strings.ToLower(name) {
+			return &guild, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r memoryGuilds) Create(_ context.Context, guild *models.Guild) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	if err := data.checkGuild(guild); err != nil {
+		return err
+	}
+	guild.ID = newID()
+	guild.CreatedAt = r.s.now()
+	data.guilds[guild.ID] = *guild
+	return nil
+}
+
+func (r memoryGuilds) Update(_ context.Context, guild *models.Guild) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	stored, ok := data.guilds[guild.ID]
+	if !ok {
+		return nil
+	}
+	if err := data.checkGuild(guild); err != nil {
+		return err
+	}
+	stored.Name, stored.RealmID, stored.Realm, stored.Faction = guild.Name, guild.RealmID, guild.Realm, guild.Faction
+	data.guilds[guild.ID] = stored
+	return nil
+}
+
+func (r memoryGuilds) ListStale(_ context.Context, before time.Time) ([]models.Guild, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	return values(data.guilds,
+		func(g models.Guild) bool { return g.RosterSyncedAt == nil || g.RosterSyncedAt.Before(before) },
+		func(a, b models.Guild) bool { return a.ID < b.ID }), nil
+}
+
+func (r memoryGuilds) SetRosterSynced(_ context.Context, id string, at time.Time) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	if guild, ok := data.guilds[id]; ok {
+		guild.RosterSyncedAt = &at
+		data.guilds[id] = guild
+	}
+	return nil
+}
+
+func (r memoryGuilds) Delete(_ context.Context, id string) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	delete(data.guilds, id)
+	for key := range data.members {
+		if key[0] == id {
+			delete(data.members, key)
+		}
+	}
+	for _, c := range data.characters {
+		if c.GuildID == id {
+			data.deleteCharacter(c.ID)
+		}
+	}
+	for _, g := range data.raidGroups {
+		if g.GuildID == id {
+			data.deleteRaidGroup(g.ID)
+		}
+	}
+	for _, e := range data.events {
+		if e.GuildID == id {
+			data.deleteEvent(e.ID)
+		}
+	}
+	for _, n := range data.notifications {
+		if n.GuildID != nil && *n.GuildID == id {
+			delete(data.notifications, n.ID)
+		}
+	}
+	return nil
+}
+
+func (r memoryGuilds) Members(_ context.Context, guildID string) ([]Member, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	members := values(data.members,
+		func(m models.GuildMember) bool { return m.GuildID == guildID },
+		func(a, b models.GuildMember) bool {
+			return data.users[a.UserID].Username < data.users[b.UserID].Username
+		})
+	list := make([]Member, len(members))
+	for i, m := range members {
+		list[i] = Member{UserID: m.UserID, Username: data.users[m.UserID].Username, Role: m.Role, JoinedAt: m.JoinedAt}
+	}
+	return list, nil
+}
+
+func (r memoryGuilds) Member(_ context.Context, guildID, userID string) (*models.GuildMember, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	member, ok := data.members[[2]string{guildID, userID}]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &member, nil
+}
+
+func (r memoryGuilds) AddMember(_ context.Context, member *models.GuildMember) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	key := [2]string{member.GuildID, member.UserID}
+	if _, ok := data.members[key]; ok {
+		return fmt.Errorf("guild member: %w", ErrConflict)
+	}
+	if _, ok := data.users[member.UserID]; !ok {
+		return fmt.Errorf("guild member references a missing user: %w", ErrConflict)
+	}
+	if _, ok := data.guilds[member.GuildID]; !ok {
+		return fmt.Errorf("guild member references a missing guild: %w", ErrConflict)
+	}
+	data.members[key] = *member
+	return nil
+}
+
+func (r memoryGuilds) SetMemberRole(_ context.Context, guildID, userID, role string) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	key := [2]string{guildID, userID}
+	if member, ok := data.members[key]; ok {
+		member.Role = role
+		data.members[key] = member
+	}
+	return nil
+}
+
+func (r memoryGuilds) RemoveMember(_ context.Context, guildID, userID string) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	delete(data.members, [2]string{guildID, userID})
+	return nil
+}
+
+func (r memoryGuilds) CountGuildMasters(_ context.Context, guildID, exceptUserID string) (int, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	count := 0
+	for key, m := range data.members {
+		if key[0] == guildID && key[1] != exceptUserID && m.Role == models.GuildRoleGuildMaster {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// checkGuild enforces the unique realm and case-insensitive name of guilds.
+func (d *memoryData) checkGuild(guild *models.Guild) error {
+	for _, g := range d.guilds {
+		if g.ID != guild.ID && g.RealmID == guild.RealmID && strings.ToLower(g.Name) == strings.ToLower(guild.Name) {
+			return fmt.Errorf("guild %s: %w", guild.Name, ErrConflict)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+)
+
+type memoryLineups struct {
+	s *memoryStore
+}
+
+func (r memoryLineups) Get(_ context.Context, eventID string) (*models.Lineup, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	lineup, ok := data.lineups[eventID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	lineup.Slots = slices.Clone(lineup.Slots)
+	return &lineup, nil
+}
+
+func (r memoryLineups) Save(_ context.Context, lineup *models.Lineup) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	if _, ok := data.events[lineup.EventID]; !ok {
+		return fmt.Errorf("lineup references a missing event: %w", ErrConflict)
+	}
+	stored, ok := data.lineups[lineup.EventID]
+	if (ok && stored.Version != lineup.Version-1) || (!ok && lineup.Version != 1) {
+		return fmt.Errorf("lineup version %d: %w", lineup.Version, ErrConflict)
+	}
+	slots := make([]models.LineupSlot, len(lineup.Slots))
+	for i, slot := range lineup.Slots {
+		if _, ok := data.characters[slot.CharacterID]; !ok {
+			return fmt.Errorf("lineup references a missing character: %w", ErrConflict)
+		}
+		slot.EventID = lineup.EventID
+		slots[i] = slot
+	}
+	slices.SortFunc(slots, func(a, b models.LineupSlot) int { return strings.Compare(a.CharacterID, b.CharacterID) })
+
+	stored = *lineup
+	stored.Slots = slots
+	data.lineups[lineup.EventID] = stored
+	lineup.Slots = slices.Clone(slots)
+	return nil
+}
+
+func (r memoryLineups) AddChanges(_ context.Context, changes []models.LineupChange) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	for i := range changes {
+		if _, ok := data.events[changes[i].EventID]; !ok {
+			return fmt.Errorf("lineup change references a missing event: %w", ErrConflict)
+		}
+		changes[i].ID = newID()
+		data.lineupChanges[changes[i].ID] = changes[i]
+	}
+	return nil
+}
+
+func (r memoryLineups) Changes(_ context.Context, eventID string) ([]models.LineupChange, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	return values(data.lineupChanges,
+		func(c models.LineupChange) bool { return c.EventID == eventID },
+		func(a, b models.LineupChange) bool {
+			if !a.ChangedAt.Equal(b.ChangedAt) {
+				return a.ChangedAt.Before(b.ChangedAt)
+			}
+			if a.Version != b.Version {
+				return a.Version < b.Version
+			}
+			return a.CharacterName < b.CharacterName
+		}), nil
+}
+
+type memoryNotifications struct {
+	s *memoryStore
+}
+
+func (r memoryNotifications) List(_ context.Context, userID string, unreadOnly bool, p Page) ([]models.Notification, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	list := values(data.notifications,
+		func(n models.Notification) bool { return n.UserID == userID && (!unreadOnly || n.ReadAt == nil) },
+		func(a, b models.Notification) bool { return a.CreatedAt.After(b.CreatedAt) })
+	return page(list, p), nil
+}
+
+func (r memoryNotifications) Create(_ context.Context, notifications []models.Notification) error {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	for i := range notifications {
+		if _, ok := data.users[notifications[i].UserID]; !ok {
+			return fmt.Errorf("notification references a missing user: %w", ErrConflict)
+		}
+		notifications[i].ID = newID()
+		if notifications[i].CreatedAt.IsZero() {
+			notifications[i].CreatedAt = r.s.now()
+		}
+		data.notifications[notifications[i].ID] = notifications[i]
+	}
+	return nil
+}
+
+func (r memoryNotifications) MarkRead(_ context.Context, userID, id string, at time.Time) (*models.Notification, error) {
+	data, unlock := r.s.lock()
+	defer unlock()
+
+	notification, ok := data.notifications[id]
+	if !ok || notification.UserID != userID {
+		return nil, ErrNotFound
+	}
+	if notification.ReadAt == nil {
+		notification.ReadAt = &at
+		data.notifications[id] = notification
+	}
+	return &notification, nil
+}
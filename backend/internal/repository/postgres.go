@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/realms"
+)
+
+// postgresStore implements Store with GORM. Constraint violations are
+// returned as the driver's *pgconn.PgError.
+type postgresStore struct {
+	db *gorm.DB
+}
+
+// NewPostgresStore returns a Store backed by db.
+func NewPostgresStore(db *gorm.DB) Store {
+	return &postgresStore{db: db}
+}
+
+func (s *postgresStore) Users() UserRepository                 { return postgresUsers{s.db} }
+func (s *postgresStore) Realms() RealmRepository               { return postgresRealms{s.db} }
+func (s *postgresStore) Guilds() GuildRepository               { return postgresGuilds{s.db} }
+func (s *postgresStore) Characters() CharacterRepository       { return postgresCharacters{s.db} }
+func (s *postgresStore) RaidGroups() RaidGroupRepository       { return postgresRaidGroups{s.db} }
+func (s *postgresStore) Events() EventRepository               { return postgresEvents{s.db} }
+func (s *postgresStore) Confirmations() ConfirmationRepository { return postgresConfirmations{s.db} }
+func (s *postgresStore) Attendances() AttendanceRepository     { return postgresAttendances{s.db} }
+func (s *postgresStore) Lineups() LineupRepository             { return postgresLineups{s.db} }
+func (s *postgresStore) Notifications() NotificationRepository { return postgresNotifications{s.db} }
+
+func (s *postgresStore) Transaction(ctx context.Context, fn func(tx Store) error) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&postgresStore{db: tx})
+	})
+}
+
+// notFound maps GORM's missing row error to ErrNotFound.
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// paged applies a Page to a query.
+func paged(query *gorm.DB, page Page) *gorm.DB {
+	if page.Limit > 0 {
+		query = query.Limit(page.Limit)
+	}
+	if page.Offset > 0 {
+		query = query.Offset(page.Offset)
+	}
+	return query
+}
+
+type postgresUsers struct {
+	db *gorm.DB
+}
+
+func (r postgresUsers) Get(ctx context.Context, id string) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).First(&user, "id = ?", id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
+
+func (r postgresUsers) GetByFeedToken(ctx context.Context, token string) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).First(&user, "feed_token = ?", token).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
+
+func (r postgresUsers) Create(ctx context.Context, user *models.User) error {
+	return r.db.WithContext(ctx).Create(user).Error
+}
+
+// Upsert omits Email, which Battle.net never provides, so the unique column
+// stays NULL instead of colliding on empty strings.
+func (r postgresUsers) Upsert(ctx context.Context, user *models.User) error {
+	return r.db.WithContext(ctx).
+		Omit("Email").
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "battle_net_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"username", "updated_at"}),
+		}).
+		Create(user).Error
+}
+
+func (r postgresUsers) SetFeedToken(ctx context.Context, id, token string) error {
+	result := r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Update("feed_token", token)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+type postgresRealms struct {
+	db *gorm.DB
+}
+
+func (r postgresRealms) List(ctx context.Context, region string, page Page) ([]models.Realm, error) {
+	query := r.db.WithContext(ctx).Order("region, name")
+	if region != "" {
+		query = query.Where("region = ?", region)
+	}
+
+	var list []models.Realm
+	if err := paged(query, page).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r postgresRealms) Get(ctx context.Context, id string) (*models.Realm, error) {
+	var realm models.Realm
+	if err := r.db.WithContext(ctx).First(&realm, "id = ?", id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &realm, nil
+}
+
+func (r postgresRealms) Resolve(ctx context.Context, region, name string) (*models.Realm, error) {
+	return realms.Resolve(r.db.WithContext(ctx), region, name)
+}
+
+func (r postgresRealms) SetConnectedRealm(ctx context.Context, id string, connectedRealmID int64) error {
+	return r.db.WithContext(ctx).Model(&models.Realm{}).Where("id = ?", id).Update("connected_realm_id", connectedRealmID).Error
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+)
+
+type postgresAttendances struct {
+	db *gorm.DB
+}
+
+func (r postgresAttendances) List(ctx context.Context, eventID string) ([]models.Attendance, error) {
+	var records []models.Attendance
+	if err := r.db.WithContext(ctx).Where("event_id = ?", eventID).Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (r postgresAttendances) Save(ctx context.Context, records []models.Attendance) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "event_id"}, {Name: "character_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "note", "recorded_by", "recorded_at"}),
+	}).Create(&records).Error
+}
+
+func (r postgresAttendances) Delete(ctx context.Context, eventID, characterID string) error {
+	result := r.db.WithContext(ctx).
+		Where("event_id = ? AND character_id = ?", eventID, characterID).
+		Delete(&models.Attendance{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r postgresAttendances) Counts(ctx context.Context, f AttendanceFilter) ([]AttendanceCounts, error) {
+	query := r.db.WithContext(ctx).Table("attendances a").
+		Select(`a.character_id, c.name, c.realm,
+			COUNT(*) AS events,
+			COUNT(*) FILTER (WHERE a.status = ?) AS present,
+			COUNT(*) FILTER (WHERE a.status = ?) AS late,
+			COUNT(*) FILTER (WHERE a.status = ?) AS absent,
+			COUNT(*) FILTER (WHERE a.status = ?) AS benched,
+			COUNT(*) FILTER (WHERE a.status = ?) AS excused,
+			COUNT(*) FILTER (WHERE a.status = ? AND conf.status = ?) AS no_shows,
+			COUNT(*) FILTER (WHERE conf.status = ?) AS declined`,
+			models.AttendancePresent, models.AttendanceLate, models.AttendanceAbsent,
+			models.AttendanceBenched, models.AttendanceExcused,
+			models.AttendanceAbsent, models.ConfirmationConfirmed, models.ConfirmationDeclined).
+		Joins("JOIN events e ON e.id = a.event_id").
+		Joins("JOIN characters c ON c.id = a.character_id").
+		Joins("LEFT JOIN confirmations conf ON conf.event_id = a.event_id AND conf.character_id = a.character_id").
+		Where("e.guild_id = ? AND e.scheduled_at >= ? AND e.scheduled_at < ?", f.GuildID, f.From, f.To).
+		Group("a.character_id, c.name, c.realm").
+		Order("c.name, c.realm")
+	if f.RaidGroupID != "" {
+		query = query.Where("e.raid_group_id = ?", f.RaidGroupID)
+	}
+
+	var counts []AttendanceCounts
+	if err := query.Scan(&counts).Error; err != nil {
+		return nil, fmt.Errorf("failed to count attendance: %w", err)
+	}
+	return counts, nil
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+)
+
+type postgresCharacters struct {
+	db *gorm.DB
+}
+
+func (r postgresCharacters) List(ctx context.Context, filter CharacterFilter) ([]models.Character, error) {
+	query := r.db.WithContext(ctx).Where("guild_id = ?", filter.GuildID).Order("name")
+	if !filter.IncludeLeft {
+		query = query.Where("left_guild_at IS NULL")
+	}
+	if filter.Class != "" {
+		query = query.Where("class = ?", filter.Class)
+	}
+
+	var characters []models.Character
+	if err := paged(query, filter.Page).Find(&characters).Error; err != nil {
+		return nil, err
+	}
+	return characters, nil
+}
+
+func (r postgresCharacters) Get(ctx context.Context, guildID, id string) (*models.Character, error) {
+	var character models.Character
+	if err := r.db.WithContext(ctx).First(&character, "id = ? AND guild_id = ?", id, guildID).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &character, nil
+}
+
+func (r postgresCharacters) GetMany(ctx context.Context, guildID string, ids []string) ([]models.Character, error) {
+	var characters []models.Character
+	if len(ids) == 0 {
+		return characters, nil
+	}
+	if err := r.db.WithContext(ctx).Where("id IN ? AND guild_id = ?", ids, guildID).Find(&characters).Error; err != nil {
+		return nil, err
+	}
+	return characters, nil
+}
+
+func (r postgresCharacters) Create(ctx context.Context, character *models.Character) error {
+	return r.db.WithContext(ctx).Create(character).Error
+}
+
+func (r postgresCharacters) Update(ctx context.Context, character *models.Character) error {
+	return r.db.WithContext(ctx).Model(character).
+		Select("name", "realm_id", "realm", "class", "spec", "preferred_role", "off_spec", "ilvl", "level", "user_id", "raid_group_id").
+		Updates(character).Error
+}
+
+func (r postgresCharacters) Delete(ctx context.Context, character *models.Character) error {
+	return r.db.WithContext(ctx).Delete(character).Error
+}
+
+func (r postgresCharacters) FindByName(ctx context.Context, realmID, name string) (*models.Character, error) {
+	var character models.Character
+	if err := r.db.WithContext(ctx).First(&character, "realm_id = ? AND lower(name) = lower(?)", realmID, name).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &character, nil
+}
+
+func (r postgresCharacters) GetByIDs(ctx context.Context, ids []string) ([]models.Character, error) {
+	var characters []models.Character
+	if len(ids) == 0 {
+		return characters, nil
+	}
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&characters).Error; err != nil {
+		return nil, err
+	}
+	return characters, nil
+}
+
+func (r postgresCharacters) SaveRoster(ctx context.Context, character *models.Character) error {
+	return r.db.WithContext(ctx).Model(character).
+		Select("guild_id", "class", "level", "guild_rank", "left_guild_at").
+		Updates(character).Error
+}
+
+func (r postgresCharacters) ListForSync(ctx context.Context, filter SyncFilter) ([]string, error) {
+	query := r.db.WithContext(ctx).Model(&models.Character{}).
+		Where("left_guild_at IS NULL").
+		Order("last_synced ASC NULLS FIRST")
+	if filter.GuildID != "" {
+		query = query.Where("guild_id = ?", filter.GuildID)
+	}
+	if !filter.SyncedBefore.IsZero() {
+		query = query.Where("last_synced IS NULL OR last_synced < ?", filter.SyncedBefore)
+	}
+
+	var ids []string
+	if err := query.Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r postgresCharacters) SaveSync(ctx context.Context, character *models.Character) error {
+	return r.db.WithContext(ctx).Model(character).
+		Select("class", "spec", "ilvl", "last_synced", "sync_error").
+		Updates(character).Error
+}
+
+func (r postgresCharacters) SetSyncError(ctx context.Context, id, message string) error {
+	return r.db.WithContext(ctx).Model(&models.Character{}).Where("id = ?", id).Update("sync_error", message).Error
+}
+
+type postgresRaidGroups struct {
+	db *gorm.DB
+}
+
+func (r postgresRaidGroups) List(ctx context.Context, guildID string, page Page) ([]models.RaidGroup, error) {
+	var groups []models.RaidGroup
+	if err := paged(r.db.WithContext(ctx).Where("guild_id = ?", guildID).Order("name"), page).Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+func (r postgresRaidGroups) ListScheduled(ctx context.Context) ([]models.RaidGroup, error) {
+	var groups []models.RaidGroup
+	if err := r.db.WithContext(ctx).Where("schedule IS NOT NULL").Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+func (r postgresRaidGroups) Get(ctx context.Context, guildID, id string, withCharacters bool) (*models.RaidGroup, error) {
+	query := r.db.WithContext(ctx)
+	if withCharacters {
+		query = query.Preload("Characters")
+	}
+
+	var group models.RaidGroup
+	if err := query.First(&group, "id = ? AND guild_id = ?", id, guildID).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &group, nil
+}
+
+func (r postgresRaidGroups) Create(ctx context.Context, group *models.RaidGroup) error {
+	return r.db.WithContext(ctx).Create(group).Error
+}
+
+func (r postgresRaidGroups) Update(ctx context.Context, group *models.RaidGroup) error {
+	return r.db.WithContext(ctx).Model(group).Select("name", "schedule").Updates(group).Error
+}
+
+func (r postgresRaidGroups) Delete(ctx context.Context, group *models.RaidGroup) error {
+	return r.db.WithContext(ctx).Delete(group).Error
+}
+
+func (r postgresRaidGroups) SetCharacters(ctx context.Context, group *models.RaidGroup, characters []models.Character) error {
+	if err := r.db.WithContext(ctx).Model(group).Association("Characters").Replace(characters); err != nil {
+		return err
+	}
+	group.Characters = characters
+	return nil
+}
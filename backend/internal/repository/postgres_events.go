@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+)
+
+type postgresEvents struct {
+	db *gorm.DB
+}
+
+func (r postgresEvents) List(ctx context.Context, filter EventFilter) ([]models.Event, error) {
+	query := r.db.WithContext(ctx).Order("scheduled_at")
+	if filter.GuildID != "" {
+		query = query.Where("guild_id = ?", filter.GuildID)
+	}
+	if filter.UserID != "" {
+		query = query.Where("id IN (?)", r.db.Table("confirmations").
+			Select("confirmations.event_id").
+			Joins("JOIN characters ON characters.id = confirmations.character_id").
+			Where("characters.user_id = ?", filter.UserID))
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("scheduled_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("scheduled_at < ?", filter.To)
+	}
+
+	var events []models.Event
+	if err := paged(query, filter.Page).Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r postgresEvents) Get(ctx context.Context, guildID, id string) (*models.Event, error) {
+	var event models.Event
+	if err := r.db.WithContext(ctx).First(&event, "id = ? AND guild_id = ?", id, guildID).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &event, nil
+}
+
+func (r postgresEvents) Create(ctx context.Context, event *models.Event) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+func (r postgresEvents) Update(ctx context.Context, event *models.Event) error {
+	err := r.db.WithContext(ctx).Model(event).Updates(map[string]interface{}{
+		"raid_name":     event.RaidName,
+		"difficulty":    event.Difficulty,
+		"scheduled_at":  event.ScheduledAt,
+		"ends_at":       event.EndsAt,
+		"raid_group_id": event.RaidGroupID,
+		"sequence":      gorm.Expr("sequence + 1"),
+	}).Error
+	if err != nil {
+		return err
+	}
+	event.Sequence++
+	return nil
+}
+
+func (r postgresEvents) Delete(ctx context.Context, event *models.Event) error {
+	return r.db.WithContext(ctx).Delete(event).Error
+}
+
+func (r postgresEvents) CreateOccurrence(ctx context.Context, event *models.Event) (bool, error) {
+	if event.RaidGroupID == nil || event.OccurrenceDate == nil {
+		return false, fmt.Errorf("scheduled events need a raid group and an occurrence date")
+	}
+
+	var id string
+	err := r.db.WithContext(ctx).Raw(`
+		INSERT INTO events (raid_name, difficulty, scheduled_at, ends_at, guild_id, raid_group_id, occurrence_date)
+		VALUES (?, ?, ?, ?, ?, ?, ?::date)
+		ON CONFLICT (raid_group_id, occurrence_date) DO NOTHING
+		RETURNING id`,
+		event.RaidName, event.Difficulty, event.ScheduledAt, event.EndsAt, event.GuildID, *event.RaidGroupID,
+		event.OccurrenceDate.Format(models.ScheduleDateLayout),
+	).Scan(&id).Error
+	if err != nil {
+		return false, err
+	}
+	if id == "" {
+		return false, nil
+	}
+	event.ID = id
+	return true, nil
+}
+
+type postgresConfirmations struct {
+	db *gorm.DB
+}
+
+func (r postgresConfirmations) List(ctx context.Context, eventID, status string) ([]models.Confirmation, error) {
+	query := r.db.WithContext(ctx).Where("event_id = ?", eventID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var confirmations []models.Confirmation
+	if err := query.Find(&confirmations).Error; err != nil {
+		return nil, err
+	}
+	return confirmations, nil
+}
+
+func (r postgresConfirmations) Get(ctx context.Context, eventID, id string) (*models.Confirmation, error) {
+	var confirmation models.Confirmation
+	if err := r.db.WithContext(ctx).First(&confirmation, "id = ? AND event_id = ?", id, eventID).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &confirmation, nil
+}
+
+func (r postgresConfirmations) Find(ctx context.Context, eventID, characterID string) (*models.Confirmation, error) {
+	var confirmation models.Confirmation
+	err := r.db.WithContext(ctx).First(&confirmation, "event_id = ? AND character_id = ?", eventID, characterID).Error
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &confirmation, nil
+}
+
+func (r postgresConfirmations) Create(ctx context.Context, confirmation *models.Confirmation) error {
+	return r.db.WithContext(ctx).Create(confirmation).Error
+}
+
+func (r postgresConfirmations) Update(ctx context.Context, confirmation *models.Confirmation) error {
+	return r.db.WithContext(ctx).Model(confirmation).Updates(map[string]interface{}{
+		"status":       confirmation.Status,
+		"reason":       confirmation.Reason,
+		"responded_at": confirmation.RespondedAt,
+		"late":         confirmation.Late,
+	}).Error
+}
+
+func (r postgresConfirmations) Delete(ctx context.Context, confirmation *models.Confirmation) error {
+	return r.db.WithContext(ctx).Delete(confirmation).Error
+}
+
+func (r postgresConfirmations) CreatePending(ctx context.Context, event *models.Event) error {
+	if event.RaidGroupID == nil {
+		return nil
+	}
+
+	err := r.db.WithContext(ctx).Exec(`
+		INSERT INTO confirmations (event_id, character_id, status)
+		SELECT ?, rgc.character_id, ?
+		FROM raid_group_characters rgc
+		JOIN characters c ON c.id = rgc.character_id
+		WHERE rgc.raid_group_id = ? AND c.left_guild_at IS NULL
+		ON CONFLICT (event_id, character_id) DO NOTHING`,
+		event.ID, models.ConfirmationPending, *event.RaidGroupID,
+	).Error
+	if err != nil {
+		return fmt.Errorf("failed to create pending confirmations: %w", err)
+	}
+	return nil
+}
+
+func (r postgresConfirmations) CreatePendingForGroup(ctx context.Context, raidGroupID string, now time.Time) error {
+	var events []models.Event
+	if err := r.db.WithContext(ctx).Where("raid_group_id = ? AND scheduled_at > ?", raidGroupID, now).Find(&events).Error; err != nil {
+		return fmt.Errorf("failed to load upcoming events: %w", err)
+	}
+	for i := range events {
+		if err := r.CreatePending(ctx, &events[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r postgresConfirmations) ForUser(ctx context.Context, userID string, eventIDs []string) ([]CharacterRSVP, error) {
+	var rsvps []CharacterRSVP
+	if len(eventIDs) == 0 {
+		return rsvps, nil
+	}
+	err := r.db.WithContext(ctx).Table("confirmations").
+		Select("confirmations.event_id, characters.name AS character_name, confirmations.status").
+		Joins("JOIN characters ON characters.id = confirmations.character_id").
+		Where("characters.user_id = ? AND confirmations.event_id IN ?", userID, eventIDs).
+		Order("characters.name").
+		Scan(&rsvps).Error
+	if err != nil {
+		return nil, err
+	}
+	return rsvps, nil
+}
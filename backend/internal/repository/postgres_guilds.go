@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+)
+
+type postgresGuilds struct {
+	db *gorm.DB
+}
+
+func (r postgresGuilds) List(ctx context.Context, page Page) ([]models.Guild, error) {
+	var guilds []models.Guild
+	if err := paged(r.db.WithContext(ctx).Order("name"), page).Find(&guilds).Error; err != nil {
+		return nil, err
+	}
+	return guilds, nil
+}
+
+func (r postgresGuilds) ListForUser(ctx context.Context, userID string) ([]models.Guild, error) {
+	var guilds []models.Guild
+	err := r.db.WithContext(ctx).
+		Joins("JOIN guild_members ON guild_members.guild_id = guilds.id").
+		Where("guild_members.user_id = ?", userID).
+		Order("guilds.name").
+		Find(&guilds).Error
+	if err != nil {
+		return nil, err
+	}
+	return guilds, nil
+}
+
+func (r postgresGuilds) Get(ctx context.Context, id string) (*models.Guild, error) {
+	var guild models.Guild
+	if err := r.db.WithContext(ctx).First(&guild, "id = ?", id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &guild, nil
+}
+
+func (r postgresGuilds) Find(ctx context.Context, region, realmSlug, name string) (*models.Guild, error) {
+	var guild models.Guild
+	err := r.db.WithContext(ctx).
+		Joins("JOIN realms ON realms.id = guilds.realm_id").
+		Where("realms.region = ? AND realms.slug = ?", region, realmSlug).
+		Where("lower(guilds.name) = lower(?)", name).
+		First(&guild).Error
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &guild, nil
+}
+
+func (r postgresGuilds) Create(ctx context.Context, guild *models.Guild) error {
+	return r.db.WithContext(ctx).Create(guild).Error
+}
+
+func (r postgresGuilds) Update(ctx context.Context, guild *models.Guild) error {
+	return r.db.WithContext(ctx).Model(guild).Select("name", "realm_id", "realm", "faction").Updates(guild).Error
+}
+
+func (r postgresGuilds) ListStale(ctx context.Context, before time.Time) ([]models.Guild, error) {
+	var guilds []models.Guild
+	err := r.db.WithContext(ctx).
+		Where("roster_synced_at IS NULL OR roster_synced_at < ?", before).
+		Find(&guilds).Error
+	if err != nil {
+		return nil, err
+	}
+	return guilds, nil
+}
+
+func (r postgresGuilds) SetRosterSynced(ctx context.Context, id string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.Guild{}).Where("id = ?", id).Update("roster_synced_at", at).Error
+}
+
+// Delete relies on ON DELETE CASCADE for everything below the guild.
+func (r postgresGuilds) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Delete(&models.Guild{}, "id = ?", id).Error
+}
+
+func (r postgresGuilds) Members(ctx context.Context, guildID string) ([]Member, error) {
+	var members []Member
+	err := r.db.WithContext(ctx).
+		Table("guild_members").
+		Select("guild_members.user_id, users.username, guild_members.role, guild_members.joined_at").
+		Joins("JOIN users ON users.id = guild_members.user_id").
+		Where("guild_members.guild_id = ?", guildID).
+		Order("users.username").
+		Scan(&members).Error
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (r postgresGuilds) Member(ctx context.Context, guildID, userID string) (*models.GuildMember, error) {
+	var member models.GuildMember
+	if err := r.db.WithContext(ctx).First(&member, "user_id = ? AND guild_id = ?", userID, guildID).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &member, nil
+}
+
+func (r postgresGuilds) AddMember(ctx context.Context, member *models.GuildMember) error {
+	return r.db.WithContext(ctx).Create(member).Error
+}
+
+func (r postgresGuilds) SetMemberRole(ctx context.Context, guildID, userID, role string) error {
+	return r.db.WithContext(ctx).Model(&models.GuildMember{}).
+		Where("user_id = ? AND guild_id = ?", userID, guildID).
+		Update("role", role).Error
+}
+
+func (r postgresGuilds) RemoveMember(ctx context.Context, guildID, userID string) error {
+	return r.db.WithContext(ctx).Where("user_id = ? AND guild_id = ?", userID, guildID).Delete(&models.GuildMember{}).Error
+}
+
+func (r postgresGuilds) CountGuildMasters(ctx context.Context, guildID, exceptUserID string) (int, error) {
+	query := r.db.WithContext(ctx).Model(&models.GuildMember{}).
+		Where("guild_id = ? AND role = ?", guildID, models.GuildRoleGuildMaster)
+	if exceptUserID != "" {
+		query = query.Where("user_id <> ?", exceptUserID)
+	}
+
+	var count int64
+	err := query.Count(&count).Error
+	return int(count), err
+}
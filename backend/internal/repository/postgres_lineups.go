@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+)
+
+type postgresLineups struct {
+	db *gorm.DB
+}
+
+func (r postgresLineups) Get(ctx context.Context, eventID string) (*models.Lineup, error) {
+	var lineup models.Lineup
+	err := r.db.WithContext(ctx).
+		Preload("Slots", func(db *gorm.DB) *gorm.DB { return db.Order("character_id") }).
+		First(&lineup, "event_id = ?", eventID).Error
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &lineup, nil
+}
+
+// Save compares and swaps the version, so of two concurrent saves of the
+// same version only one succeeds.
+func (r postgresLineups) Save(ctx context.Context, lineup *models.Lineup) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var result *gorm.DB
+		if lineup.Version == 1 {
+			result = tx.Exec(`
+				INSERT INTO lineups (event_id, version, locked_at, locked_by, updated_by, updated_at)
+				VALUES (?, 1, ?, ?, ?, ?)
+				ON CONFLICT (event_id) DO NOTHING`,
+				lineup.EventID, lineup.LockedAt, lineup.LockedBy, lineup.UpdatedBy, lineup.UpdatedAt)
+		} else {
+			result = tx.Model(&models.Lineup{}).
+				Where("event_id = ? AND version = ?", lineup.EventID, lineup.Version-1).
+				Updates(map[string]interface{}{
+					"version":    lineup.Version,
+					"locked_at":  lineup.LockedAt,
+					"locked_by":  lineup.LockedBy,
+					"updated_by": lineup.UpdatedBy,
+					"updated_at": lineup.UpdatedAt,
+				})
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("lineup version %d: %w", lineup.Version, ErrConflict)
+		}
+
+		if err := tx.Where("event_id = ?", lineup.EventID).Delete(&models.LineupSlot{}).Error; err != nil {
+			return err
+		}
+		if len(lineup.Slots) == 0 {
+			return nil
+		}
+		for i := range lineup.Slots {
+			lineup.Slots[i].EventID = lineup.EventID
+		}
+		return tx.Create(&lineup.Slots).Error
+	})
+}
+
+func (r postgresLineups) AddChanges(ctx context.Context, changes []models.LineupChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&changes).Error
+}
+
+func (r postgresLineups) Changes(ctx context.Context, eventID string) ([]models.LineupChange, error) {
+	var changes []models.LineupChange
+	err := r.db.WithContext(ctx).
+		Where("event_id = ?", eventID).
+		Order("changed_at, version, character_name").
+		Find(&changes).Error
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+type postgresNotifications struct {
+	db *gorm.DB
+}
+
+func (r postgresNotifications) List(ctx context.Context, userID string, unreadOnly bool, page Page) ([]models.Notification, error) {
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC")
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var notifications []models.Notification
+	if err := paged(query, page).Find(&notifications).Error; err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+func (r postgresNotifications) Create(ctx context.Context, notifications []models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&notifications).Error
+}
+
+func (r postgresNotifications) MarkRead(ctx context.Context, userID, id string, at time.Time) (*models.Notification, error) {
+	err := r.db.WithContext(ctx).Model(&models.Notification{}).
+		Where("id = ? AND user_id = ? AND read_at IS NULL", id, userID).
+		Update("read_at", at).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var notification models.Notification
+	if err := r.db.WithContext(ctx).First(&notification, "id = ? AND user_id = ?", id, userID).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &notification, nil
+}
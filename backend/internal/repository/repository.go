@@ -0,0 +1,296 @@
+// Package repository defines the storage interfaces of the guild manager's
+// entities, with a Postgres implementation for production and an in-memory
+// one for tests that should not need a database.
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+)
+
+var (
+	// ErrNotFound is returned when a looked-up row does not exist.
+	ErrNotFound = errors.New("record not found")
+	// ErrConflict is returned by the in-memory store for writes Postgres
+	// would reject with a unique or foreign key violation, and by both
+	// stores when a lineup was saved concurrently.
+	ErrConflict = errors.New("conflicting record")
+)
+
+// Page limits a list to Limit rows after skipping Offset. A zero Limit
+// returns every row.
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// CharacterFilter selects a guild's characters, ordered by name.
+type CharacterFilter struct {
+	GuildID string
+	// Class is ignored when empty; characters that left the in-game guild
+	// are only included with IncludeLeft.
+	Class       string
+	IncludeLeft bool
+	Page        Page
+}
+
+// SyncFilter selects the characters still in their in-game guild to
+// refresh from Battle.net, least recently synced first. Zero fields are
+// ignored.
+type SyncFilter struct {
+	GuildID string
+	// SyncedBefore limits the list to characters never synced or last
+	// synced before it.
+	SyncedBefore time.Time
+}
+
+// EventFilter selects events ordered by start time. Zero fields are ignored.
+type EventFilter struct {
+	GuildID string
+	// UserID limits the list to events one of the user's characters has a
+	// confirmation for.
+	UserID string
+	From   time.Time
+	To     time.Time
+	Page   Page
+}
+
+// Member is a guild member with their username.
+type Member struct {
+	UserID   string    `json:"user_id"`
+	Username string    `json:"username"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// AttendanceFilter selects the events of GuildID scheduled within
+// [From, To), only those of RaidGroupID unless it is empty.
+type AttendanceFilter struct {
+	GuildID     string
+	RaidGroupID string
+	From        time.Time
+	To          time.Time
+}
+
+// AttendanceCounts are a character's attendance records by status over the
+// events of an AttendanceFilter.
+type AttendanceCounts struct {
+	CharacterID string `json:"character_id"`
+	Name        string `json:"name"`
+	Realm       string `json:"realm"`
+	Events      int    `json:"events"`
+	Present     int    `json:"present"`
+	Late        int    `json:"late"`
+	Absent      int    `json:"absent"`
+	Benched     int    `json:"benched"`
+	Excused     int    `json:"excused"`
+	// NoShows are absences after confirming; Declined counts events the
+	// character declined, whatever was recorded.
+	NoShows  int `json:"no_shows"`
+	Declined int `json:"declined"`
+}
+
+// CharacterRSVP is the answer of one of a user's characters to an event.
+type CharacterRSVP struct {
+	EventID       string
+	CharacterName string
+	Status        string
+}
+
+// Store gives access to every repository. Repositories of the store passed
+// to Transaction's callback run inside the transaction.
+type Store interface {
+	Users() UserRepository
+	Realms() RealmRepository
+	Guilds() GuildRepository
+	Characters() CharacterRepository
+	RaidGroups() RaidGroupRepository
+	Events() EventRepository
+	Confirmations() ConfirmationRepository
+	Attendances() AttendanceRepository
+	Lineups() LineupRepository
+	Notifications() NotificationRepository
+
+	// Transaction runs fn in a transaction that is committed when fn
+	// returns nil and rolled back otherwise.
+	Transaction(ctx context.Context, fn func(tx Store) error) error
+}
+
+// UserRepository stores users.
+type UserRepository interface {
+	Get(ctx context.Context, id string) (*models.User, error)
+	GetByFeedToken(ctx context.Context, token string) (*models.User, error)
+	Create(ctx context.Context, user *models.User) error
+	// Upsert creates the user, or updates the username of the one with the
+	// same Battle.net ID, and fills in its ID.
+	Upsert(ctx context.Context, user *models.User) error
+	SetFeedToken(ctx context.Context, id, token string) error
+}
+
+// RealmRepository stores realms.
+type RealmRepository interface {
+	// List lists realms by region and name, only those of region unless it
+	// is empty.
+	List(ctx context.Context, region string, page Page) ([]models.Realm, error)
+	Get(ctx context.Context, id string) (*models.Realm, error)
+	// Resolve returns the realm called name in region, creating it if needed.
+	Resolve(ctx context.Context, region, name string) (*models.Realm, error)
+	// SetConnectedRealm stores the realm's connected-realm group.
+	SetConnectedRealm(ctx context.Context, id string, connectedRealmID int64) error
+}
+
+// GuildRepository stores guilds and their members.
+type GuildRepository interface {
+	List(ctx context.Context, page Page) ([]models.Guild, error)
+	// ListForUser lists the guilds userID is a member of.
+	ListForUser(ctx context.Context, userID string) ([]models.Guild, error)
+	Get(ctx context.Context, id string) (*models.Guild, error)
+	// Find returns the guild called name, ignoring case, on the realm of
+	// region with realmSlug.
+	Find(ctx context.Context, region, realmSlug, name string) (*models.Guild, error)
+	Create(ctx context.Context, guild *models.Guild) error
+	// Update saves the guild's name, realm and faction.
+	Update(ctx context.Context, guild *models.Guild) error
+	// ListStale lists the guilds whose roster was never reconciled or last
+	// reconciled before before.
+	ListStale(ctx context.Context, before time.Time) ([]models.Guild, error)
+	// SetRosterSynced records when the guild's roster was reconciled.
+	SetRosterSynced(ctx context.Context, id string, at time.Time) error
+	// Delete removes the guild with its members, characters, raid groups and
+	// events.
+	Delete(ctx context.Context, id string) error
+
+	Members(ctx context.Context, guildID string) ([]Member, error)
+	Member(ctx context.Context, guildID, userID string) (*models.GuildMember, error)
+	AddMember(ctx context.Context, member *models.GuildMember) error
+	SetMemberRole(ctx context.Context, guildID, userID, role string) error
+	RemoveMember(ctx context.Context, guildID, userID string) error
+	// CountGuildMasters counts the guild masters other than exceptUserID,
+	// or all of them when it is empty.
+	CountGuildMasters(ctx context.Context, guildID, exceptUserID string) (int, error)
+}
+
+// CharacterRepository stores characters. Characters are looked up within a
+// guild, except by the roster import and the character sync, which follow
+// characters across guilds.
+type CharacterRepository interface {
+	List(ctx context.Context, filter CharacterFilter) ([]models.Character, error)
+	Get(ctx context.Context, guildID, id string) (*models.Character, error)
+	// GetMany returns the characters of ids that belong to the guild.
+	GetMany(ctx context.Context, guildID string, ids []string) ([]models.Character, error)
+	Create(ctx context.Context, character *models.Character) error
+	// Update saves every field the API lets clients change.
+	Update(ctx context.Context, character *models.Character) error
+	Delete(ctx context.Context, character *models.Character) error
+
+	// FindByName returns the character called name, ignoring case, on the
+	// realm, whatever its guild.
+	FindByName(ctx context.Context, realmID, name string) (*models.Character, error)
+	// GetByIDs returns the characters of ids, whatever their guild.
+	GetByIDs(ctx context.Context, ids []string) ([]models.Character, error)
+	// SaveRoster saves the fields a roster reconciliation maintains: guild,
+	// class, level, guild rank and left_guild_at.
+	SaveRoster(ctx context.Context, character *models.Character) error
+	// ListForSync lists the IDs of the characters to refresh.
+	ListForSync(ctx context.Context, filter SyncFilter) ([]string, error)
+	// SaveSync saves the outcome of a successful sync: class, spec, item
+	// level and last_synced, clearing sync_error.
+	SaveSync(ctx context.Context, character *models.Character) error
+	// SetSyncError records why the character could not be synced.
+	SetSyncError(ctx context.Context, id, message string) error
+}
+
+// RaidGroupRepository stores raid groups and their characters.
+type RaidGroupRepository interface {
+	List(ctx context.Context, guildID string, page Page) ([]models.RaidGroup, error)
+	// ListScheduled lists the raid groups of every guild that have a schedule.
+	ListScheduled(ctx context.Context) ([]models.RaidGroup, error)
+	// Get loads the group, with its characters when withCharacters is set.
+	Get(ctx context.Context, guildID, id string, withCharacters bool) (*models.RaidGroup, error)
+	Create(ctx context.Context, group *models.RaidGroup) error
+	// Update saves the group's name and schedule.
+	Update(ctx context.Context, group *models.RaidGroup) error
+	Delete(ctx context.Context, group *models.RaidGroup) error
+	// SetCharacters replaces the group's characters.
+	SetCharacters(ctx context.Context, group *models.RaidGroup, characters []models.Character) error
+}
+
+// EventRepository stores events.
+type EventRepository interface {
+	List(ctx context.Context, filter EventFilter) ([]models.Event, error)
+	Get(ctx context.Context, guildID, id string) (*models.Event, error)
+	Create(ctx context.Context, event *models.Event) error
+	// Update saves the event's editable fields and increments its sequence.
+	Update(ctx context.Context, event *models.Event) error
+	Delete(ctx context.Context, event *models.Event) error
+	// CreateOccurrence creates a raid group's scheduled event unless the
+	// group already has one on its occurrence date, reporting whether it did.
+	CreateOccurrence(ctx context.Context, event *models.Event) (bool, error)
+}
+
+// ConfirmationRepository stores the RSVPs of characters to events.
+type ConfirmationRepository interface {
+	// List lists the event's confirmations, only those with status unless it
+	// is empty.
+	List(ctx context.Context, eventID, status string) ([]models.Confirmation, error)
+	Get(ctx context.Context, eventID, id string) (*models.Confirmation, error)
+	// Find returns the character's confirmation of the event.
+	Find(ctx context.Context, eventID, characterID string) (*models.Confirmation, error)
+	Create(ctx context.Context, confirmation *models.Confirmation) error
+	// Update saves the answer: status, reason, response time and lateness.
+	Update(ctx context.Context, confirmation *models.Confirmation) error
+	Delete(ctx context.Context, confirmation *models.Confirmation) error
+
+	// CreatePending inserts a pending confirmation for every active
+	// character of the event's raid group that has none yet. Events without
+	// a raid group are left alone.
+	CreatePending(ctx context.Context, event *models.Event) error
+	// CreatePendingForGroup does the same for every event of the raid group
+	// starting after now.
+	CreatePendingForGroup(ctx context.Context, raidGroupID string, now time.Time) error
+	// ForUser lists the answers of the user's characters to eventIDs.
+	ForUser(ctx context.Context, userID string, eventIDs []string) ([]CharacterRSVP, error)
+}
+
+// AttendanceRepository stores what happened at events, one record per event
+// and character.
+type AttendanceRepository interface {
+	List(ctx context.Context, eventID string) ([]models.Attendance, error)
+	// Save creates the records, replacing those of the same event and
+	// character.
+	Save(ctx context.Context, records []models.Attendance) error
+	// Delete removes the character's record of the event, failing with
+	// ErrNotFound when there is none.
+	Delete(ctx context.Context, eventID, characterID string) error
+	// Counts counts the records of every character with one in the
+	// filter's events, ordered by name and realm.
+	Counts(ctx context.Context, filter AttendanceFilter) ([]AttendanceCounts, error)
+}
+
+// LineupRepository stores the lineups of events and their audit trail.
+type LineupRepository interface {
+	// Get returns the event's lineup with its slots ordered by character.
+	Get(ctx context.Context, eventID string) (*models.Lineup, error)
+	// Save creates or replaces the lineup and its slots. Its Version must be
+	// one more than the stored one, or 1 for a new lineup; otherwise Save
+	// fails with ErrConflict.
+	Save(ctx context.Context, lineup *models.Lineup) error
+	// AddChanges appends to the audit trail.
+	AddChanges(ctx context.Context, changes []models.LineupChange) error
+	// Changes lists the event's audit trail, oldest first.
+	Changes(ctx context.Context, eventID string) ([]models.LineupChange, error)
+}
+
+// NotificationRepository stores the notifications of users.
+type NotificationRepository interface {
+	// List lists the user's notifications, newest first, only unread ones
+	// when unreadOnly is set.
+	List(ctx context.Context, userID string, unreadOnly bool, page Page) ([]models.Notification, error)
+	Create(ctx context.Context, notifications []models.Notification) error
+	// MarkRead marks the user's notification as read at at, unless it
+	// already is, and returns it.
+	MarkRead(ctx context.Context, userID, id string, at time.Time) (*models.Notification, error)
+}
@@ -0,0 +1,346 @@
+package roster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+	"github.com/GFerreiroS/guild-manager/backend/pkg/blizzard"
+)
+
+// ErrNotLeader is returned by Import when the claimant had to take over a
+// guild without a guild master but cannot: none of their characters leads it
+// in game, or someone else claimed it first.
+var ErrNotLeader = errors.New("none of the user's characters leads the guild")
+
+// Change describes a character affected by a roster reconciliation.
+type Change struct {
+	CharacterID string   `json:"character_id"`
+	Name        string   `json:"name"`
+	Realm       string   `json:"realm"`
+	Fields      []string `json:"fields,omitempty"` // Changed columns, only set for changed characters
+}
+
+// Diff summarizes a roster reconciliation.
+type Diff struct {
+	Added   []Change `json:"added"`
+	Removed []Change `json:"removed"`
+	Changed []Change `json:"changed"`
+}
+
+// Importer pulls guild rosters from the Blizzard API into characters rows.
+type Importer struct {
+	store  repository.Store
+	client *blizzard.Client
+}
+
+// NewImporter creates an Importer.
+func NewImporter(store repository.Store, client *blizzard.Client) *Importer {
+	return &Importer{store: store, client: client}
+}
+
+// Region is the Battle.net region rosters are imported from.
+func (i *Importer) Region() string {
+	return i.client.Region()
+}
+
+// Imported is the outcome of an import.
+type Imported struct {
+	Guild   *models.Guild
+	Roster  *Diff
+	Created bool // The guild was not known yet
+	Claimed bool // The importing user became the guild master
+}
+
+// Claimant is the user importing a guild.
+type Claimant struct {
+	UserID string
+	// Characters are the blizzard.CharacterKey of the characters Battle.net
+	// says the user's account owns.
+	Characters []string
+	// MustClaim makes Import fail with ErrNotLeader, before changing
+	// anything, unless the user becomes the guild master.
+	MustClaim bool
+}
+
+// Exists reports whether Battle.net knows the guild called name on realm.
+func (i *Importer) Exists(ctx context.Context, name, realm string) (bool, error) {
+	_, err := i.client.GuildRoster(ctx, realm, name)
+	if blizzard.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch roster for %s-%s: %w", name, realm, err)
+	}
+	return true, nil
+}
+
+// Import creates the guild if it is not known yet and reconciles its roster.
+// The claimant becomes guild master of a guild that has none only when one of
+// their account's characters leads it in game (rank 0); otherwise a site
+// admin has to appoint one.
+func (i *Importer) Import(ctx context.Context, name, realm string, claimant Claimant) (*Imported, error) {
+	roster, err := i.client.GuildRoster(ctx, realm, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch roster for %s-%s: %w", name, realm, err)
+	}
+
+	leads := leadsGuild(roster, claimant.Characters)
+	if claimant.MustClaim && !leads {
+		return nil, ErrNotLeader
+	}
+
+	guildRealm, err := i.store.Realms().Resolve(ctx, i.client.Region(), roster.Guild.Realm.Name)
+	if err != nil {
+		return nil, err
+	}
+	i.lookupConnected(ctx, guildRealm)
+
+	guild, err := i.store.Guilds().Find(ctx, guildRealm.Region, guildRealm.Slug, roster.Guild.Name)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("failed to look up guild: %w", err)
+	}
+
+	result := &Imported{Created: guild == nil}
+	err = i.store.Transaction(ctx, func(tx repository.Store) error {
+		if result.Created {
+			guild = &models.Guild{
+				Name:      roster.Guild.Name,
+				RealmID:   guildRealm.ID,
+				Realm:     guildRealm.Name,
+				Faction:   strings.ToLower(roster.Guild.Faction.Type),
+				CreatedBy: claimant.UserID,
+			}
+			if err := tx.Guilds().Create(ctx, guild); err != nil {
+				return fmt.Errorf("failed to create guild: %w", err)
+			}
+		}
+		if !leads {
+			return nil
+		}
+
+		masters, err := tx.Guilds().CountGuildMasters(ctx, guild.ID, "")
+		if err != nil {
+			return fmt.Errorf("failed to look up guild masters: %w", err)
+		}
+		if masters > 0 {
+			if claimant.MustClaim {
+				return ErrNotLeader
+			}
+			return nil
+		}
+		// The user may already be a member of a guild nobody leads yet.
+		_, err = tx.Guilds().Member(ctx, guild.ID, claimant.UserID)
+		switch {
+		case err == nil:
+			err = tx.Guilds().SetMemberRole(ctx, guild.ID, claimant.UserID, models.GuildRoleGuildMaster)
+		case errors.Is(err, repository.ErrNotFound):
+			err = tx.Guilds().AddMember(ctx, &models.GuildMember{
+				UserID:   claimant.UserID,
+				GuildID:  guild.ID,
+				JoinedAt: time.Now().UTC(),
+				Role:     models.GuildRoleGuildMaster,
+			})
+		}
+		if err != nil {
+			return fmt.Errorf("failed to add guild master: %w", err)
+		}
+		result.Claimed = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Guild = guild
+
+	result.Roster, err = i.reconcile(ctx, guild, roster)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// leadsGuild reports whether one of characters is the rank 0 member of the
+// fetched roster.
+func leadsGuild(roster *blizzard.GuildRoster, characters []string) bool {
+	for _, member := range roster.Members {
+		if member.Rank == 0 && slices.Contains(characters, blizzard.CharacterKey(member.Character.Realm.Name, member.Character.Name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupConnected fills in the realm's connected-realm group from Battle.net
+// if it is not known yet. Failures are logged, not returned, since the group
+// is informational.
+func (i *Importer) lookupConnected(ctx context.Context, realm *models.Realm) {
+	if realm.ConnectedRealmID != nil || realm.Region != i.client.Region() {
+		return
+	}
+
+	info, err := i.client.Realm(ctx, realm.Slug)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to look up connected realm", "region", realm.Region, "realm", realm.Slug, "error", err)
+		return
+	}
+	id := info.ConnectedRealmID()
+	if id == 0 {
+		return
+	}
+	if err := i.store.Realms().SetConnectedRealm(ctx, realm.ID, id); err != nil {
+		slog.ErrorContext(ctx, "failed to store connected realm", "region", realm.Region, "realm", realm.Slug, "error", err)
+		return
+	}
+	realm.ConnectedRealmID = &id
+}
+
+// Reconcile fetches the guild's in-game roster and brings its characters in
+// line with it.
+func (i *Importer) Reconcile(ctx context.Context, guild *models.Guild) (*Diff, error) {
+	roster, err := i.client.GuildRoster(ctx, guild.Realm, guild.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch roster for %s-%s: %w", guild.Name, guild.Realm, err)
+	}
+	return i.reconcile(ctx, guild, roster)
+}
+
+// reconcile applies a fetched roster: new members are added, existing ones
+// updated, and characters no longer on the roster are flagged with
+// left_guild_at rather than deleted.
+func (i *Importer) reconcile(ctx context.Context, guild *models.Guild, roster *blizzard.GuildRoster) (*Diff, error) {
+	diff := &Diff{Added: []Change{}, Removed: []Change{}, Changed: []Change{}}
+	now := time.Now().UTC()
+
+	err := i.store.Transaction(ctx, func(tx repository.Store) error {
+		existing, err := tx.Characters().List(ctx, repository.CharacterFilter{GuildID: guild.ID, IncludeLeft: true})
+		if err != nil {
+			return fmt.Errorf("failed to load guild characters: %w", err)
+		}
+		byKey := make(map[string]*models.Character, len(existing))
+		for idx := range existing {
+			byKey[blizzard.CharacterKey(existing[idx].Realm, existing[idx].Name)] = &existing[idx]
+		}
+
+		// Members may come from other (connected) realms of the region.
+		realmsBySlug := map[string]*models.Realm{}
+		realmFor := func(name string) (*models.Realm, error) {
+			slug := blizzard.Slug(name)
+			if realm, ok := realmsBySlug[slug]; ok {
+				return realm, nil
+			}
+			realm, err := tx.Realms().Resolve(ctx, i.client.Region(), name)
+			if err != nil {
+				return nil, err
+			}
+			realmsBySlug[slug] = realm
+			return realm, nil
+		}
+
+		seen := make(map[string]bool, len(roster.Members))
+		for _, member := range roster.Members {
+			class := blizzard.ClassSlug(member.Character.PlayableClass.ID)
+			if class == "" {
+				continue
+			}
+			key := blizzard.CharacterKey(member.Character.Realm.Name, member.Character.Name)
+			seen[key] = true
+			rank := member.Rank
+
+			character, ok := byKey[key]
+			if !ok {
+				realm, err := realmFor(member.Character.Realm.Name)
+				if err != nil {
+					return err
+				}
+
+				// The character may exist under another guild (name and
+				// realm are unique), in which case it moves to this one.
+				other, err := tx.Characters().FindByName(ctx, realm.ID, member.Character.Name)
+				if errors.Is(err, repository.ErrNotFound) {
+					created := models.Character{
+						Name:      member.Character.Name,
+						RealmID:   realm.ID,
+						Realm:     realm.Name,
+						Class:     class,
+						Level:     member.Character.Level,
+						GuildRank: &rank,
+						GuildID:   guild.ID,
+					}
+					if err := tx.Characters().Create(ctx, &created); err != nil {
+						return fmt.Errorf("failed to create character %s: %w", created.Name, err)
+					}
+					diff.Added = append(diff.Added, changeFor(&created, nil))
+					continue
+				}
+				if err != nil {
+					return fmt.Errorf("failed to look up character %s: %w", member.Character.Name, err)
+				}
+				character = other
+			}
+
+			rejoined := character.GuildID != guild.ID || character.LeftGuildAt != nil
+			var fields []string
+			if character.Class != class {
+				character.Class = class
+				fields = append(fields, "class")
+			}
+			if character.Level != member.Character.Level {
+				character.Level = member.Character.Level
+				fields = append(fields, "level")
+			}
+			if character.GuildRank == nil || *character.GuildRank != rank {
+				character.GuildRank = &rank
+				fields = append(fields, "guild_rank")
+			}
+			if !rejoined && len(fields) == 0 {
+				continue
+			}
+
+			character.GuildID, character.LeftGuildAt = guild.ID, nil
+			if err := tx.Characters().SaveRoster(ctx, character); err != nil {
+				return fmt.Errorf("failed to update character %s: %w", character.Name, err)
+			}
+			if rejoined {
+				diff.Added = append(diff.Added, changeFor(character, nil))
+			} else {
+				diff.Changed = append(diff.Changed, changeFor(character, fields))
+			}
+		}
+
+		for idx := range existing {
+			character := &existing[idx]
+			if character.LeftGuildAt != nil || seen[blizzard.CharacterKey(character.Realm, character.Name)] {
+				continue
+			}
+			character.LeftGuildAt = &now
+			if err := tx.Characters().SaveRoster(ctx, character); err != nil {
+				return fmt.Errorf("failed to flag character %s as left: %w", character.Name, err)
+			}
+			diff.Removed = append(diff.Removed, changeFor(character, nil))
+		}
+
+		return tx.Guilds().SetRosterSynced(ctx, guild.ID, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+	guild.RosterSyncedAt = &now
+
+	return diff, nil
+}
+
+func changeFor(character *models.Character, fields []string) Change {
+	return Change{
+		CharacterID: character.ID,
+		Name:        character.Name,
+		Realm:       character.Realm,
+		Fields:      fields,
+	}
+}
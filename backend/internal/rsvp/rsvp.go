@@ -0,0 +1,29 @@
+package rsvp
+
+import (
+	"time"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+)
+
+// Respond applies a member's answer to a confirmation. RespondedAt is only
+// touched when the answer actually changes, and answers given less than
+// cutoff before the event starts are flagged as late. It reports whether
+// anything changed.
+func Respond(confirmation *models.Confirmation, event *models.Event, status, reason string, now time.Time, cutoff time.Duration) bool {
+	if confirmation.Status == status && confirmation.Reason == reason && confirmation.RespondedAt != nil {
+		return false
+	}
+
+	confirmation.Status = status
+	confirmation.Reason = reason
+	confirmation.RespondedAt = &now
+	confirmation.Late = IsLate(event, now, cutoff)
+	return true
+}
+
+// IsLate reports whether an answer given at now falls after the event's RSVP
+// cutoff.
+func IsLate(event *models.Event, now time.Time, cutoff time.Duration) bool {
+	return now.After(event.ScheduledAt.Add(-cutoff))
+}
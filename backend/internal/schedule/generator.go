@@ -0,0 +1,127 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+)
+
+// Generator materializes events from raid group schedules.
+type Generator struct {
+	store   repository.Store
+	horizon time.Duration
+	tick    time.Duration
+}
+
+// NewGenerator creates a Generator that keeps events weeksAhead weeks ahead,
+// checking every tick.
+func NewGenerator(store repository.Store, weeksAhead int, tick time.Duration) *Generator {
+	return &Generator{
+		store:   store,
+		horizon: time.Duration(weeksAhead) * 7 * 24 * time.Hour,
+		tick:    tick,
+	}
+}
+
+// Run generates events every tick until ctx is cancelled.
+func (g *Generator) Run(ctx context.Context) {
+	ticker := time.NewTicker(g.tick)
+	defer ticker.Stop()
+
+	for {
+		created, err := g.GenerateAll(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "event generation failed", "error", err)
+		} else if created > 0 {
+			slog.InfoContext(ctx, "event generation finished", "created", created)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// GenerateAll generates events for every raid group with a schedule. A group
+// whose generation fails is logged and skipped.
+func (g *Generator) GenerateAll(ctx context.Context) (int, error) {
+	groups, err := g.store.RaidGroups().ListScheduled(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load raid groups: %w", err)
+	}
+
+	now := time.Now().UTC()
+	total := 0
+	for i := range groups {
+		var created int
+		err := g.store.Transaction(ctx, func(tx repository.Store) error {
+			var err error
+			created, err = g.GenerateGroup(ctx, tx, &groups[i], now)
+			return err
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "event generation failed for raid group", "raid_group_id", groups[i].ID, "error", err)
+			continue
+		}
+		total += created
+	}
+	return total, nil
+}
+
+// GenerateGroup creates the group's missing events between now and the
+// horizon in store and asks its characters to respond. Occurrences that
+// already have an event, even one edited since, are left alone. It returns
+// the number of events created.
+func (g *Generator) GenerateGroup(ctx context.Context, store repository.Store, group *models.RaidGroup, now time.Time) (int, error) {
+	if group.Schedule == nil {
+		return 0, nil
+	}
+	occurrences, err := Occurrences(group.Schedule, now, now.Add(g.horizon))
+	if err != nil {
+		return 0, err
+	}
+
+	raidName, difficulty := group.Schedule.RaidName, group.Schedule.Difficulty
+	if raidName == "" {
+		raidName = group.Name
+	}
+	if difficulty == "" {
+		difficulty = "normal"
+	}
+
+	created := 0
+	for _, o := range occurrences {
+		date, err := time.Parse(models.ScheduleDateLayout, o.Date)
+		if err != nil {
+			return created, err
+		}
+		endsAt := o.EndsAt
+		event := models.Event{
+			RaidName:       raidName,
+			Difficulty:     difficulty,
+			ScheduledAt:    o.StartsAt,
+			EndsAt:         &endsAt,
+			GuildID:        group.GuildID,
+			RaidGroupID:    &group.ID,
+			OccurrenceDate: &date,
+		}
+		ok, err := store.Events().CreateOccurrence(ctx, &event)
+		if err != nil {
+			return created, fmt.Errorf("failed to create event for %s: %w", o.Date, err)
+		}
+		if !ok {
+			continue
+		}
+		if err := store.Confirmations().CreatePending(ctx, &event); err != nil {
+			return created, err
+		}
+		created++
+	}
+	return created, nil
+}
@@ -0,0 +1,61 @@
+package schedule
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+)
+
+// Occurrence is a single raid night of a schedule.
+type Occurrence struct {
+	// Date is the local date in the schedule's time zone (YYYY-MM-DD).
+	Date     string
+	StartsAt time.Time
+	EndsAt   time.Time
+}
+
+// Occurrences lists the occurrences of a schedule starting within
+// [from, to), in UTC. The schedule must be valid.
+func Occurrences(s *models.RaidSchedule, from, to time.Time) ([]Occurrence, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	loc, _ := time.LoadLocation(s.Timezone)
+	clock, _ := time.Parse(models.ScheduleTimeLayout, s.StartTime)
+
+	weekdays := make(map[time.Weekday]bool, len(s.Weekdays))
+	for _, day := range s.Weekdays {
+		weekdays[time.Weekday(slices.Index(models.Weekdays, strings.ToLower(day)))] = true
+	}
+
+	// Days are walked as local dates, kept at UTC midnight so that adding a
+	// day never lands on a DST gap.
+	var occurrences []Occurrence
+	first, last := from.In(loc), to.In(loc)
+	day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, time.UTC)
+	end := time.Date(last.Year(), last.Month(), last.Day(), 0, 0, 0, 0, time.UTC)
+	for ; !day.After(end); day = day.AddDate(0, 0, 1) {
+		date := day.Format(models.ScheduleDateLayout)
+		// Dates compare correctly as YYYY-MM-DD strings.
+		switch {
+		case !weekdays[day.Weekday()],
+			s.StartDate != "" && date < s.StartDate,
+			s.EndDate != "" && date > s.EndDate,
+			slices.Contains(s.SkipDates, date):
+			continue
+		}
+
+		startsAt := time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+		if startsAt.Before(from) || !startsAt.Before(to) {
+			continue
+		}
+		occurrences = append(occurrences, Occurrence{
+			Date:     date,
+			StartsAt: startsAt.UTC(),
+			EndsAt:   startsAt.Add(time.Duration(s.DurationMinutes) * time.Minute).UTC(),
+		})
+	}
+	return occurrences, nil
+}
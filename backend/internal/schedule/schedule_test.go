@@ -0,0 +1,67 @@
+package schedule
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+)
+
+func TestOccurrences(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule models.RaidSchedule
+		from, to string
+		want     []string
+	}{
+		{
+			name:     "weekdays in range",
+			schedule: models.RaidSchedule{Weekdays: []string{"wednesday", "sunday"}, StartTime: "20:00", DurationMinutes: 180, Timezone: "Europe/Madrid"},
+			from:     "2026-01-05T00:00:00Z",
+			to:       "2026-01-19T00:00:00Z",
+			want:     []string{"2026-01-07T19:00:00Z", "2026-01-11T19:00:00Z", "2026-01-14T19:00:00Z", "2026-01-18T19:00:00Z"},
+		},
+		{
+			// Tuesday 00:30 in Tokyo is Monday 15:30 UTC, before to even
+			// though to is still Monday in UTC.
+			name:     "last local day ahead of UTC",
+			schedule: models.RaidSchedule{Weekdays: []string{"tuesday"}, StartTime: "00:30", DurationMinutes: 120, Timezone: "Asia/Tokyo"},
+			from:     "2026-01-05T00:00:00Z",
+			to:       "2026-01-05T16:00:00Z",
+			want:     []string{"2026-01-05T15:30:00Z"},
+		},
+		{
+			name:     "first local day behind UTC",
+			schedule: models.RaidSchedule{Weekdays: []string{"sunday"}, StartTime: "22:00", DurationMinutes: 120, Timezone: "America/Los_Angeles"},
+			from:     "2026-01-05T00:00:00Z",
+			to:       "2026-01-06T00:00:00Z",
+			want:     []string{"2026-01-05T06:00:00Z"},
+		},
+		{
+			name:     "skipped and out of bounds dates",
+			schedule: models.RaidSchedule{Weekdays: []string{"monday"}, StartTime: "20:00", DurationMinutes: 60, Timezone: "UTC", StartDate: "2026-01-12", EndDate: "2026-01-26", SkipDates: []string{"2026-01-19"}},
+			from:     "2026-01-01T00:00:00Z",
+			to:       "2026-02-01T00:00:00Z",
+			want:     []string{"2026-01-12T20:00:00Z", "2026-01-26T20:00:00Z"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, _ := time.Parse(time.RFC3339, tt.from)
+			to, _ := time.Parse(time.RFC3339, tt.to)
+			got, err := Occurrences(&tt.schedule, from, to)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			starts := make([]string, len(got))
+			for i, o := range got {
+				starts[i] = o.StartsAt.Format(time.RFC3339)
+			}
+			if !slices.Equal(starts, tt.want) {
+				t.Fatalf("occurrences = %v, want %v", starts, tt.want)
+			}
+		})
+	}
+}
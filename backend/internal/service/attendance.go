@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/attendance"
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+)
+
+// AttendanceInput is what officers record for one character.
+type AttendanceInput struct {
+	CharacterID string
+	Status      string
+	Note        string
+}
+
+// AttendanceService records what happened at events and reports on it.
+type AttendanceService struct {
+	store repository.Store
+}
+
+func (s *AttendanceService) List(ctx context.Context, event *models.Event) ([]models.Attendance, error) {
+	return s.store.Attendances().List(ctx, event.ID)
+}
+
+// Record creates or replaces the attendance of the event's characters on
+// behalf of userID. The event must have started, and the records must be of
+// distinct characters of its guild.
+func (s *AttendanceService) Record(ctx context.Context, event *models.Event, userID string, in []AttendanceInput) ([]models.Attendance, error) {
+	now := time.Now().UTC()
+	if event.ScheduledAt.After(now) {
+		return nil, ErrEventNotStarted
+	}
+
+	records := make([]models.Attendance, len(in))
+	ids := make([]string, len(in))
+	for i, r := range in {
+		ids[i] = r.CharacterID
+		records[i] = models.Attendance{
+			EventID:     event.ID,
+			CharacterID: r.CharacterID,
+			Status:      r.Status,
+			Note:        r.Note,
+			RecordedBy:  &userID,
+			RecordedAt:  now,
+		}
+	}
+	err := s.store.Transaction(ctx, func(tx repository.Store) error {
+		characters, err := tx.Characters().GetMany(ctx, event.GuildID, ids)
+		if err != nil {
+			return err
+		}
+		if len(characters) != len(ids) {
+			return ErrForeignCharacter
+		}
+		return tx.Attendances().Save(ctx, records)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// Delete removes the character's attendance of the event.
+func (s *AttendanceService) Delete(ctx context.Context, event *models.Event, characterID string) error {
+	return s.store.Attendances().Delete(ctx, event.ID, characterID)
+}
+
+// Report reports the attendance of the filter's events.
+func (s *AttendanceService) Report(ctx context.Context, filter repository.AttendanceFilter) (*attendance.Report, error) {
+	counts, err := s.store.Attendances().Counts(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return attendance.Build(filter, counts), nil
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+)
+
+// CharacterInput is what clients may set on a character.
+type CharacterInput struct {
+	Name  string
+	Realm string
+	Class string
+	Spec  string
+	// PreferredRole overrides the role of Spec, and must be one the class
+	// can play. OffSpec must be a spec of the class.
+	PreferredRole *string
+	OffSpec       string
+	Ilvl          int
+	Level         int
+	UserID        *string
+	RaidGroupID   *string
+}
+
+// CharacterService manages a guild's characters.
+type CharacterService struct {
+	store repository.Store
+}
+
+func (s *CharacterService) List(ctx context.Context, filter repository.CharacterFilter) ([]models.Character, error) {
+	return s.store.Characters().List(ctx, filter)
+}
+
+func (s *CharacterService) Get(ctx context.Context, guildID, id string) (*models.Character, error) {
+	return s.store.Characters().Get(ctx, guildID, id)
+}
+
+func (s *CharacterService) Create(ctx context.Context, guild *models.Guild, in CharacterInput) (*models.Character, error) {
+	character := models.Character{GuildID: guild.ID}
+	err := s.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := applyCharacterInput(ctx, tx, guild, &character, in); err != nil {
+			return err
+		}
+		return tx.Characters().Create(ctx, &character)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &character, nil
+}
+
+// Update replaces every field clients may set.
+func (s *CharacterService) Update(ctx context.Context, guild *models.Guild, character *models.Character, in CharacterInput) error {
+	return s.store.Transaction(ctx, func(tx repository.Store) error {
+		updated := *character
+		if err := applyCharacterInput(ctx, tx, guild, &updated, in); err != nil {
+			return err
+		}
+		if err := tx.Characters().Update(ctx, &updated); err != nil {
+			return err
+		}
+		*character = updated
+		return nil
+	})
+}
+
+func (s *CharacterService) Delete(ctx context.Context, character *models.Character) error {
+	return s.store.Characters().Delete(ctx, character)
+}
+
+// applyCharacterInput copies in to character, resolving its realm in the
+// guild's region: the realm may differ from the guild's (connected realms,
+// cross-realm guilds) but the region may not. The raid group must be one of
+// the guild's, and a newly linked user one of its members.
+func applyCharacterInput(ctx context.Context, tx repository.Store, guild *models.Guild, character *models.Character, in CharacterInput) error {
+	if in.PreferredRole != nil && !models.CanPlay(in.Class, *in.PreferredRole) {
+		return ErrUnplayableRole
+	}
+	if in.OffSpec != "" && models.SpecRole(in.Class, in.OffSpec) == "" {
+		return ErrUnknownOffSpec
+	}
+	if in.RaidGroupID != nil {
+		_, err := tx.RaidGroups().Get(ctx, guild.ID, *in.RaidGroupID, false)
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrForeignRaidGroup
+		}
+		if err != nil {
+			return err
+		}
+	}
+	// Imported characters may keep an owner who is not a member; only a
+	// change of owner is checked.
+	if in.UserID != nil && (character.UserID == nil || *character.UserID != *in.UserID) {
+		_, err := tx.Guilds().Member(ctx, guild.ID, *in.UserID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrForeignUser
+		}
+		if err != nil {
+			return err
+		}
+	}
+	guildRealm, err := tx.Realms().Get(ctx, guild.RealmID)
+	if err != nil {
+		return err
+	}
+	realm, err := tx.Realms().Resolve(ctx, guildRealm.Region, in.Realm)
+	if err != nil {
+		return err
+	}
+
+	character.Name, character.RealmID, character.Realm = in.Name, realm.ID, realm.Name
+	character.Class, character.Spec = in.Class, in.Spec
+	character.PreferredRole, character.OffSpec = in.PreferredRole, in.OffSpec
+	character.Ilvl, character.Level = in.Ilvl, in.Level
+	character.UserID, character.RaidGroupID = in.UserID, in.RaidGroupID
+	return nil
+}
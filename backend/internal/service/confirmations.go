@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+	"github.com/GFerreiroS/guild-manager/backend/internal/rsvp"
+)
+
+// ConfirmationService records the RSVPs of characters to events.
+type ConfirmationService struct {
+	store repository.Store
+	// cutoff is how long before an event answers start being flagged late.
+	cutoff time.Duration
+}
+
+// List lists the event's confirmations, only those with status unless it is
+// empty.
+func (s *ConfirmationService) List(ctx context.Context, eventID, status string) ([]models.Confirmation, error) {
+	return s.store.Confirmations().List(ctx, eventID, status)
+}
+
+func (s *ConfirmationService) Get(ctx context.Context, eventID, id string) (*models.Confirmation, error) {
+	return s.store.Confirmations().Get(ctx, eventID, id)
+}
+
+func (s *ConfirmationService) Delete(ctx context.Context, confirmation *models.Confirmation) error {
+	return s.store.Confirmations().Delete(ctx, confirmation)
+}
+
+// Respond creates or updates the character's confirmation of the event and
+// reports whether it had to be created. Callers check that the character
+// belongs to the event's guild and that the user may answer for it.
+func (s *ConfirmationService) Respond(ctx context.Context, event *models.Event, character *models.Character, status, reason string) (*models.Confirmation, bool, error) {
+	var confirmation models.Confirmation
+	created := false
+	err := s.store.Transaction(ctx, func(tx repository.Store) error {
+		existing, err := tx.Confirmations().Find(ctx, event.ID, character.ID)
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			confirmation = models.Confirmation{EventID: event.ID, CharacterID: character.ID}
+		case err != nil:
+			return err
+		default:
+			confirmation = *existing
+		}
+
+		if !rsvp.Respond(&confirmation, event, status, reason, time.Now().UTC(), s.cutoff) {
+			return nil
+		}
+		if confirmation.ID == "" {
+			created = true
+			return tx.Confirmations().Create(ctx, &confirmation)
+		}
+		return tx.Confirmations().Update(ctx, &confirmation)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return &confirmation, created, nil
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/composition"
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+)
+
+// EventInput is what clients may set on an event. When a raid group is
+// given, its characters get pending confirmations.
+type EventInput struct {
+	RaidName    string
+	Difficulty  string
+	ScheduledAt time.Time
+	EndsAt      *time.Time
+	RaidGroupID *string
+}
+
+// EventService manages events.
+type EventService struct {
+	store repository.Store
+}
+
+func (s *EventService) List(ctx context.Context, filter repository.EventFilter) ([]models.Event, error) {
+	return s.store.Events().List(ctx, filter)
+}
+
+func (s *EventService) Get(ctx context.Context, guildID, id string) (*models.Event, error) {
+	return s.store.Events().Get(ctx, guildID, id)
+}
+
+// GetWithConfirmations returns an event together with its confirmations.
+func (s *EventService) GetWithConfirmations(ctx context.Context, guildID, id string) (*models.Event, error) {
+	event, err := s.store.Events().Get(ctx, guildID, id)
+	if err != nil {
+		return nil, err
+	}
+	if event.Confirmations, err = s.store.Confirmations().List(ctx, event.ID, ""); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// Composition breaks down the event's confirmed and tentative characters by
+// role, class and armor type.
+func (s *EventService) Composition(ctx context.Context, event *models.Event) (*composition.Composition, error) {
+	confirmations, err := s.store.Confirmations().List(ctx, event.ID, "")
+	if err != nil {
+		return nil, err
+	}
+	characters, err := s.store.Characters().List(ctx, repository.CharacterFilter{GuildID: event.GuildID, IncludeLeft: true})
+	if err != nil {
+		return nil, err
+	}
+	return composition.Build(confirmations, characters), nil
+}
+
+// Create creates an event of the guild on behalf of userID. The raid group,
+// if any, must belong to the guild.
+func (s *EventService) Create(ctx context.Context, guildID, userID string, in EventInput) (*models.Event, error) {
+	event := models.Event{
+		RaidName:    in.RaidName,
+		Difficulty:  in.Difficulty,
+		ScheduledAt: in.ScheduledAt,
+		EndsAt:      in.EndsAt,
+		CreatedBy:   &userID,
+		GuildID:     guildID,
+		RaidGroupID: in.RaidGroupID,
+	}
+	err := s.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := checkRaidGroup(ctx, tx, guildID, in.RaidGroupID); err != nil {
+			return err
+		}
+		if err := tx.Events().Create(ctx, &event); err != nil {
+			return err
+		}
+		return tx.Confirmations().CreatePending(ctx, &event)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// Update replaces the event's fields and bumps its sequence so calendar
+// clients pick up the change. Confirmations of a previous raid group are
+// kept.
+func (s *EventService) Update(ctx context.Context, event *models.Event, in EventInput) error {
+	return s.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := checkRaidGroup(ctx, tx, event.GuildID, in.RaidGroupID); err != nil {
+			return err
+		}
+		updated := *event
+		updated.RaidName, updated.Difficulty = in.RaidName, in.Difficulty
+		updated.ScheduledAt, updated.EndsAt, updated.RaidGroupID = in.ScheduledAt, in.EndsAt, in.RaidGroupID
+		if err := tx.Events().Update(ctx, &updated); err != nil {
+			return err
+		}
+		if err := tx.Confirmations().CreatePending(ctx, &updated); err != nil {
+			return err
+		}
+		*event = updated
+		return nil
+	})
+}
+
+// Delete removes an event. Deleting a generated event skips its date in the
+// raid group's schedule, so the generator does not recreate it.
+func (s *EventService) Delete(ctx context.Context, event *models.Event) error {
+	return s.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := tx.Events().Delete(ctx, event); err != nil {
+			return err
+		}
+		return skipOccurrence(ctx, tx, event)
+	})
+}
+
+// skipOccurrence adds the date of a deleted generated event to its raid
+// group's skip dates.
+func skipOccurrence(ctx context.Context, tx repository.Store, event *models.Event) error {
+	if event.OccurrenceDate == nil || event.RaidGroupID == nil {
+		return nil
+	}
+
+	group, err := tx.RaidGroups().Get(ctx, event.GuildID, *event.RaidGroupID, false)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if group.Schedule == nil {
+		return nil
+	}
+	date := event.OccurrenceDate.Format(models.ScheduleDateLayout)
+	if slices.Contains(group.Schedule.SkipDates, date) {
+		return nil
+	}
+	group.Schedule.SkipDates = append(group.Schedule.SkipDates, date)
+	return tx.RaidGroups().Update(ctx, group)
+}
+
+// checkRaidGroup fails with ErrForeignRaidGroup unless the raid group is nil
+// or belongs to the guild.
+func checkRaidGroup(ctx context.Context, tx repository.Store, guildID string, raidGroupID *string) error {
+	if raidGroupID == nil {
+		return nil
+	}
+	_, err := tx.RaidGroups().Get(ctx, guildID, *raidGroupID, false)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrForeignRaidGroup
+	}
+	return err
+}
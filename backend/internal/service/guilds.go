@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+)
+
+// GuildInput is what clients may set on a guild. Region defaults to the
+// configured Battle.net region.
+type GuildInput struct {
+	Name    string
+	Realm   string
+	Region  string
+	Faction string
+}
+
+// GuildService manages guilds and their members.
+type GuildService struct {
+	store  repository.Store
+	region string
+}
+
+func (s *GuildService) List(ctx context.Context, page repository.Page) ([]models.Guild, error) {
+	return s.store.Guilds().List(ctx, page)
+}
+
+// ListForUser lists the guilds userID is a member of.
+func (s *GuildService) ListForUser(ctx context.Context, userID string) ([]models.Guild, error) {
+	return s.store.Guilds().ListForUser(ctx, userID)
+}
+
+func (s *GuildService) Get(ctx context.Context, id string) (*models.Guild, error) {
+	return s.store.Guilds().Get(ctx, id)
+}
+
+// Find returns the guild called name on the realm with realmSlug in region.
+func (s *GuildService) Find(ctx context.Context, region, realmSlug, name string) (*models.Guild, error) {
+	return s.store.Guilds().Find(ctx, region, realmSlug, name)
+}
+
+// LedByOthers reports whether the guild has a guild master other than
+// userID.
+func (s *GuildService) LedByOthers(ctx context.Context, guildID, userID string) (bool, error) {
+	count, err := s.store.Guilds().CountGuildMasters(ctx, guildID, userID)
+	return count > 0, err
+}
+
+// Create creates a guild and makes userID its guild master.
+func (s *GuildService) Create(ctx context.Context, userID string, in GuildInput) (*models.Guild, error) {
+	guild := models.Guild{Name: in.Name, Faction: in.Faction, CreatedBy: userID}
+	err := s.store.Transaction(ctx, func(tx repository.Store) error {
+		realm, err := tx.Realms().Resolve(ctx, s.regionOr(in.Region), in.Realm)
+		if err != nil {
+			return err
+		}
+		guild.RealmID, guild.Realm = realm.ID, realm.Name
+		if err := tx.Guilds().Create(ctx, &guild); err != nil {
+			return err
+		}
+		return tx.Guilds().AddMember(ctx, &models.GuildMember{
+			UserID:   userID,
+			GuildID:  guild.ID,
+			JoinedAt: time.Now().UTC(),
+			Role:     models.GuildRoleGuildMaster,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &guild, nil
+}
+
+// Update replaces the guild's name, realm and faction.
+func (s *GuildService) Update(ctx context.Context, guild *models.Guild, in GuildInput) error {
+	return s.store.Transaction(ctx, func(tx repository.Store) error {
+		realm, err := tx.Realms().Resolve(ctx, s.regionOr(in.Region), in.Realm)
+		if err != nil {
+			return err
+		}
+		updated := *guild
+		updated.Name, updated.RealmID, updated.Realm, updated.Faction = in.Name, realm.ID, realm.Name, in.Faction
+		if err := tx.Guilds().Update(ctx, &updated); err != nil {
+			return err
+		}
+		*guild = updated
+		return nil
+	})
+}
+
+// Delete removes a guild with its characters, raid groups and events.
+func (s *GuildService) Delete(ctx context.Context, guild *models.Guild) error {
+	return s.store.Guilds().Delete(ctx, guild.ID)
+}
+
+func (s *GuildService) Members(ctx context.Context, guildID string) ([]repository.Member, error) {
+	return s.store.Guilds().Members(ctx, guildID)
+}
+
+// GuildStatus summarizes a guild for the dashboard.
+type GuildStatus struct {
+	Guild models.Guild `json:"guild"`
+	// Members counts the users in the guild, Characters the characters
+	// still in the in-game guild.
+	Members    int           `json:"members"`
+	Characters int           `json:"characters"`
+	NextRaid   *models.Event `json:"next_raid"`
+	// LastSynced is the most recent character sync, nil if none ran yet.
+	LastSynced *time.Time `json:"last_synced"`
+}
+
+// Status summarizes the guild as of now.
+func (s *GuildService) Status(ctx context.Context, guild *models.Guild, now time.Time) (*GuildStatus, error) {
+	members, err := s.store.Guilds().Members(ctx, guild.ID)
+	if err != nil {
+		return nil, err
+	}
+	characters, err := s.store.Characters().List(ctx, repository.CharacterFilter{GuildID: guild.ID})
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.store.Events().List(ctx, repository.EventFilter{
+		GuildID: guild.ID,
+		From:    now,
+		Page:    repository.Page{Limit: 1},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	status := &GuildStatus{Guild: *guild, Members: len(members), Characters: len(characters)}
+	if len(events) > 0 {
+		status.NextRaid = &events[0]
+	}
+	for i := range characters {
+		synced := characters[i].LastSynced
+		if !synced.IsZero() && (status.LastSynced == nil || synced.After(*status.LastSynced)) {
+			status.LastSynced = &synced
+		}
+	}
+	return status, nil
+}
+
+// PutMember adds a user to the guild or changes their role. A guild master
+// cannot be demoted while nobody else is one.
+func (s *GuildService) PutMember(ctx context.Context, guildID, userID, role string) (*models.GuildMember, error) {
+	var member *models.GuildMember
+	err := s.store.Transaction(ctx, func(tx repository.Store) error {
+		existing, err := tx.Guilds().Member(ctx, guildID, userID)
+		if errors.Is(err, repository.ErrNotFound) {
+			member = &models.GuildMember{UserID: userID, GuildID: guildID, Role: role, JoinedAt: time.Now().UTC()}
+			return tx.Guilds().AddMember(ctx, member)
+		}
+		if err != nil {
+			return err
+		}
+
+		if existing.Role == models.GuildRoleGuildMaster && role != models.GuildRoleGuildMaster {
+			if err := ensureAnotherGuildMaster(ctx, tx, guildID, userID); err != nil {
+				return err
+			}
+		}
+		member = existing
+		member.Role = role
+		return tx.Guilds().SetMemberRole(ctx, guildID, userID, role)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// RemoveMember removes a user from the guild, unless they are its last
+// guild master.
+func (s *GuildService) RemoveMember(ctx context.Context, guildID, userID string) error {
+	return s.store.Transaction(ctx, func(tx repository.Store) error {
+		existing, err := tx.Guilds().Member(ctx, guildID, userID)
+		if err != nil {
+			return err
+		}
+		if existing.Role == models.GuildRoleGuildMaster {
+			if err := ensureAnotherGuildMaster(ctx, tx, guildID, userID); err != nil {
+				return err
+			}
+		}
+		return tx.Guilds().RemoveMember(ctx, guildID, userID)
+	})
+}
+
+// ensureAnotherGuildMaster fails unless someone other than userID is a guild
+// master of the guild.
+func ensureAnotherGuildMaster(ctx context.Context, tx repository.Store, guildID, userID string) error {
+	count, err := tx.Guilds().CountGuildMasters(ctx, guildID, userID)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrLastGuildMaster
+	}
+	return nil
+}
+
+// regionOr returns region, or the configured region when it is empty.
+func (s *GuildService) regionOr(region string) string {
+	if region == "" {
+		return s.region
+	}
+	return region
+}
@@ -0,0 +1,331 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+)
+
+// LineupInput is the lineup officers submit: the characters of each status.
+// Characters left out are not part of the lineup.
+type LineupInput struct {
+	// Version is the lineup version the officer edited. When set, saving
+	// fails with ErrStaleLineup if someone else saved in between.
+	Version  *int
+	Selected []string
+	Standby  []string
+	Bench    []string
+}
+
+// LineupService manages the lineups of events.
+type LineupService struct {
+	store repository.Store
+}
+
+// lineupPhrases describe the status of a character in notifications; the
+// empty status is a character removed from the lineup.
+var lineupPhrases = map[string]string{
+	models.LineupSelected: "was selected for",
+	models.LineupStandby:  "is on standby for",
+	models.LineupBench:    "was benched for",
+	"":                    "was removed from the lineup of",
+}
+
+// Get returns the event's lineup, an empty version 0 one if none was saved.
+func (s *LineupService) Get(ctx context.Context, event *models.Event) (*models.Lineup, error) {
+	return getLineup(ctx, s.store, event.ID)
+}
+
+// History lists the event's lineup changes, oldest first.
+func (s *LineupService) History(ctx context.Context, event *models.Event) ([]models.LineupChange, error) {
+	return s.store.Lineups().Changes(ctx, event.ID)
+}
+
+// Save replaces the event's lineup on behalf of userID. Selected characters
+// must have confirmed and fit the difficulty's cap; standby and benched ones
+// may also be tentative. Each save that changes something bumps the version
+// and is written to the audit trail. Once the lineup is locked, the owners of
+// the characters that moved are notified.
+func (s *LineupService) Save(ctx context.Context, event *models.Event, userID string, in LineupInput) (*models.Lineup, error) {
+	slots, err := lineupSlots(in)
+	if err != nil {
+		return nil, err
+	}
+	if selected := countSlots(slots, models.LineupSelected); selected > models.LineupCaps[event.Difficulty] {
+		return nil, fmt.Errorf("%w: at most %d for %s", ErrLineupFull, models.LineupCaps[event.Difficulty], event.Difficulty)
+	}
+
+	var lineup *models.Lineup
+	err = s.store.Transaction(ctx, func(tx repository.Store) error {
+		current, err := getLineup(ctx, tx, event.ID)
+		if err != nil {
+			return err
+		}
+		if in.Version != nil && *in.Version != current.Version {
+			return ErrStaleLineup
+		}
+
+		characters, err := lineupCharacters(ctx, tx, event, current.Slots, slots)
+		if err != nil {
+			return err
+		}
+		if err := checkAvailable(ctx, tx, event, slots, characters); err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		changes := diffSlots(current, slots, characters)
+		if len(changes) == 0 {
+			lineup = current
+			return nil
+		}
+
+		next := *current
+		next.Version++
+		next.Slots = slots
+		next.UpdatedBy, next.UpdatedAt = &userID, now
+		if err := saveLineup(ctx, tx, &next); err != nil {
+			return err
+		}
+		for i := range changes {
+			changes[i].EventID, changes[i].Version = event.ID, next.Version
+			changes[i].Action, changes[i].AfterLock = models.LineupActionUpdate, current.LockedAt != nil
+			changes[i].ChangedBy, changes[i].ChangedAt = &userID, now
+		}
+		if err := tx.Lineups().AddChanges(ctx, changes); err != nil {
+			return err
+		}
+		if current.LockedAt != nil {
+			moved := map[string]string{}
+			for _, change := range changes {
+				moved[*change.CharacterID] = change.ToStatus
+			}
+			if err := notifyLineup(ctx, tx, event, moved, characters, now); err != nil {
+				return err
+			}
+		}
+		lineup = &next
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lineup, nil
+}
+
+// Lock locks the event's lineup on behalf of userID and notifies the owners
+// of the selected characters. Later saves are flagged in the audit trail.
+func (s *LineupService) Lock(ctx context.Context, event *models.Event, userID string) (*models.Lineup, error) {
+	var lineup models.Lineup
+	err := s.store.Transaction(ctx, func(tx repository.Store) error {
+		current, err := getLineup(ctx, tx, event.ID)
+		if err != nil {
+			return err
+		}
+		if current.LockedAt != nil {
+			return ErrLineupLocked
+		}
+		if countSlots(current.Slots, models.LineupSelected) == 0 {
+			return ErrEmptyLineup
+		}
+
+		now := time.Now().UTC()
+		lineup = *current
+		lineup.Version++
+		lineup.LockedAt, lineup.LockedBy = &now, &userID
+		lineup.UpdatedBy, lineup.UpdatedAt = &userID, now
+		if err := saveLineup(ctx, tx, &lineup); err != nil {
+			return err
+		}
+		err = tx.Lineups().AddChanges(ctx, []models.LineupChange{{
+			EventID:   event.ID,
+			Version:   lineup.Version,
+			Action:    models.LineupActionLock,
+			ChangedBy: &userID,
+			ChangedAt: now,
+		}})
+		if err != nil {
+			return err
+		}
+
+		selected := map[string]string{}
+		for _, slot := range lineup.Slots {
+			if slot.Status == models.LineupSelected {
+				selected[slot.CharacterID] = slot.Status
+			}
+		}
+		characters, err := lineupCharacters(ctx, tx, event, nil, lineup.Slots)
+		if err != nil {
+			return err
+		}
+		return notifyLineup(ctx, tx, event, selected, characters, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &lineup, nil
+}
+
+// getLineup returns the event's lineup, or an empty version 0 one.
+func getLineup(ctx context.Context, store repository.Store, eventID string) (*models.Lineup, error) {
+	lineup, err := store.Lineups().Get(ctx, eventID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return &models.Lineup{EventID: eventID, Slots: []models.LineupSlot{}}, nil
+	}
+	return lineup, err
+}
+
+// saveLineup saves the lineup, failing with ErrStaleLineup when another save
+// of the same version won the race.
+func saveLineup(ctx context.Context, tx repository.Store, lineup *models.Lineup) error {
+	err := tx.Lineups().Save(ctx, lineup)
+	if errors.Is(err, repository.ErrConflict) {
+		return ErrStaleLineup
+	}
+	return err
+}
+
+// lineupSlots turns the input into slots, failing with ErrLineupDuplicate
+// when a character is listed twice.
+func lineupSlots(in LineupInput) ([]models.LineupSlot, error) {
+	slots := []models.LineupSlot{}
+	for status, ids := range map[string][]string{
+		models.LineupSelected: in.Selected,
+		models.LineupStandby:  in.Standby,
+		models.LineupBench:    in.Bench,
+	} {
+		for _, id := range ids {
+			slots = append(slots, models.LineupSlot{CharacterID: id, Status: status})
+		}
+	}
+	slices.SortFunc(slots, func(a, b models.LineupSlot) int { return strings.Compare(a.CharacterID, b.CharacterID) })
+	for i := 1; i < len(slots); i++ {
+		if slots[i].CharacterID == slots[i-1].CharacterID {
+			return nil, ErrLineupDuplicate
+		}
+	}
+	return slots, nil
+}
+
+func countSlots(slots []models.LineupSlot, status string) int {
+	n := 0
+	for _, slot := range slots {
+		if slot.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// lineupCharacters loads the characters of the old and new slots by ID,
+// failing with ErrForeignCharacter if a new one is not in the event's guild.
+func lineupCharacters(ctx context.Context, tx repository.Store, event *models.Event, old, slots []models.LineupSlot) (map[string]*models.Character, error) {
+	var ids []string
+	for _, slot := range slices.Concat(old, slots) {
+		ids = append(ids, slot.CharacterID)
+	}
+	list, err := tx.Characters().GetMany(ctx, event.GuildID, ids)
+	if err != nil {
+		return nil, err
+	}
+	characters := make(map[string]*models.Character, len(list))
+	for i := range list {
+		characters[list[i].ID] = &list[i]
+	}
+	for _, slot := range slots {
+		if _, ok := characters[slot.CharacterID]; !ok {
+			return nil, ErrForeignCharacter
+		}
+	}
+	return characters, nil
+}
+
+// checkAvailable fails with ErrUnavailable unless every selected character
+// confirmed and every other one confirmed or is tentative.
+func checkAvailable(ctx context.Context, tx repository.Store, event *models.Event, slots []models.LineupSlot, characters map[string]*models.Character) error {
+	confirmations, err := tx.Confirmations().List(ctx, event.ID, "")
+	if err != nil {
+		return err
+	}
+	answers := make(map[string]string, len(confirmations))
+	for _, confirmation := range confirmations {
+		answers[confirmation.CharacterID] = confirmation.Status
+	}
+
+	for _, slot := range slots {
+		answer := answers[slot.CharacterID]
+		name := characters[slot.CharacterID].Name
+		switch {
+		case answer == models.ConfirmationConfirmed:
+		case slot.Status == models.LineupSelected:
+			return fmt.Errorf("%w: %s has not confirmed", ErrUnavailable, name)
+		case answer != models.ConfirmationTentative:
+			return fmt.Errorf("%w: %s is neither confirmed nor tentative", ErrUnavailable, name)
+		}
+	}
+	return nil
+}
+
+// diffSlots lists the characters whose status differs between the current
+// lineup and slots, by name.
+func diffSlots(current *models.Lineup, slots []models.LineupSlot, characters map[string]*models.Character) []models.LineupChange {
+	before := make(map[string]string, len(current.Slots))
+	for _, slot := range current.Slots {
+		before[slot.CharacterID] = slot.Status
+	}
+	after := make(map[string]string, len(slots))
+	for _, slot := range slots {
+		after[slot.CharacterID] = slot.Status
+	}
+
+	var changes []models.LineupChange
+	add := func(id string) {
+		if before[id] == after[id] {
+			return
+		}
+		change := models.LineupChange{CharacterID: &id, FromStatus: before[id], ToStatus: after[id]}
+		if character, ok := characters[id]; ok {
+			change.CharacterName = character.Name
+		}
+		changes = append(changes, change)
+	}
+	for _, slot := range current.Slots {
+		add(slot.CharacterID)
+	}
+	for _, slot := range slots {
+		if _, ok := before[slot.CharacterID]; !ok {
+			add(slot.CharacterID)
+		}
+	}
+	slices.SortFunc(changes, func(a, b models.LineupChange) int { return strings.Compare(a.CharacterName, b.CharacterName) })
+	return changes
+}
+
+// notifyLineup tells the owners of the characters in statuses (character ID
+// to lineup status, empty when removed) where they stand.
+func notifyLineup(ctx context.Context, tx repository.Store, event *models.Event, statuses map[string]string, characters map[string]*models.Character, now time.Time) error {
+	var notifications []models.Notification
+	for id, status := range statuses {
+		character, ok := characters[id]
+		if !ok || character.UserID == nil {
+			continue
+		}
+		notifications = append(notifications, models.Notification{
+			UserID:  *character.UserID,
+			GuildID: &event.GuildID,
+			EventID: &event.ID,
+			Kind:    models.NotificationLineup,
+			Message: fmt.Sprintf("%s %s %s (%s) on %s.", character.Name, lineupPhrases[status],
+				event.RaidName, event.Difficulty, event.ScheduledAt.UTC().Format("Mon 2 Jan 15:04 MST")),
+			CreatedAt: now,
+		})
+	}
+	slices.SortFunc(notifications, func(a, b models.Notification) int { return strings.Compare(a.Message, b.Message) })
+	return tx.Notifications().Create(ctx, notifications)
+}
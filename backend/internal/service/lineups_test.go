@@ -0,0 +1,341 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+)
+
+// lineupFixture is an event of a guild whose characters each have their own
+// owner, except the ownerless ones.
+type lineupFixture struct {
+	store   repository.Store
+	svc     *Services
+	officer *models.User
+	event   *models.Event
+	// characters are keyed by name.
+	characters map[string]*models.Character
+}
+
+func newLineupFixture(t *testing.T, difficulty string) *lineupFixture {
+	t.Helper()
+
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	f := &lineupFixture{
+		store:      store,
+		svc:        New(store, Options{Region: "eu", RSVPCutoff: time.Hour}),
+		characters: map[string]*models.Character{},
+	}
+	f.officer = f.user(t, "officer")
+	guild, err := f.svc.Guilds.Create(ctx, f.officer.ID, GuildInput{Name: "Guild", Realm: "Silvermoon", Faction: "horde"})
+	if err != nil {
+		t.Fatalf("create guild: %v", err)
+	}
+	f.event = &models.Event{
+		GuildID:     guild.ID,
+		RaidName:    "Nerub-ar Palace",
+		Difficulty:  difficulty,
+		ScheduledAt: time.Date(2026, 10, 20, 19, 0, 0, 0, time.UTC),
+	}
+	if err := store.Events().Create(ctx, f.event); err != nil {
+		t.Fatalf("create event: %v", err)
+	}
+	return f
+}
+
+func (f *lineupFixture) user(t *testing.T, name string) *models.User {
+	t.Helper()
+
+	user := &models.User{BattleNetID: name, Username: name}
+	if err := f.store.Users().Create(context.Background(), user); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user
+}
+
+// character creates a character owned by a user of the same name, or by
+// nobody, that answered the event with status unless it is empty.
+func (f *lineupFixture) character(t *testing.T, name, status string, owned bool) string {
+	t.Helper()
+
+	ctx := context.Background()
+	realm, err := f.store.Realms().Resolve(ctx, "eu", "Silvermoon")
+	if err != nil {
+		t.Fatalf("resolve realm: %v", err)
+	}
+	character := &models.Character{Name: name, RealmID: realm.ID, Realm: realm.Name, Class: "shaman", GuildID: f.event.GuildID}
+	if owned {
+		character.UserID = &f.user(t, name).ID
+	}
+	if err := f.store.Characters().Create(ctx, character); err != nil {
+		t.Fatalf("create character: %v", err)
+	}
+	if status != "" {
+		err := f.store.Confirmations().Create(ctx, &models.Confirmation{EventID: f.event.ID, CharacterID: character.ID, Status: status})
+		if err != nil {
+			t.Fatalf("create confirmation: %v", err)
+		}
+	}
+	f.characters[name] = character
+	return character.ID
+}
+
+func (f *lineupFixture) save(in LineupInput) (*models.Lineup, error) {
+	return f.svc.Lineups.Save(context.Background(), f.event, f.officer.ID, in)
+}
+
+// notifications returns the messages sent to the owner of a character.
+func (f *lineupFixture) notifications(t *testing.T, name string) []string {
+	t.Helper()
+
+	list, err := f.store.Notifications().List(context.Background(), *f.characters[name].UserID, false, repository.Page{})
+	if err != nil {
+		t.Fatalf("list notifications: %v", err)
+	}
+	var messages []string
+	for _, n := range list {
+		messages = append(messages, n.Message)
+	}
+	return messages
+}
+
+func TestSaveLineupCap(t *testing.T) {
+	tests := []struct {
+		difficulty string
+		selected   int
+		wantErr    error
+	}{
+		{"mythic", 20, nil},
+		{"mythic", 21, ErrLineupFull},
+		{"heroic", 30, nil},
+		{"heroic", 31, ErrLineupFull},
+		{"normal", 31, ErrLineupFull},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d on %s", tt.selected, tt.difficulty), func(t *testing.T) {
+			f := newLineupFixture(t, tt.difficulty)
+			var ids []string
+			for i := range tt.selected {
+				ids = append(ids, f.character(t, fmt.Sprintf("Raider%02d", i), models.ConfirmationConfirmed, false))
+			}
+
+			// Standby and benched characters do not count.
+			bench := f.character(t, "Benched", models.ConfirmationConfirmed, false)
+			lineup, err := f.save(LineupInput{Selected: ids, Bench: []string{bench}})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && len(lineup.Slots) != tt.selected+1 {
+				t.Fatalf("saved %d slots, want %d", len(lineup.Slots), tt.selected+1)
+			}
+		})
+	}
+}
+
+func TestSaveLineupAvailability(t *testing.T) {
+	tests := []struct {
+		answer  string
+		status  string
+		wantErr error
+	}{
+		{models.ConfirmationConfirmed, models.LineupSelected, nil},
+		{models.ConfirmationConfirmed, models.LineupStandby, nil},
+		{models.ConfirmationConfirmed, models.LineupBench, nil},
+		{models.ConfirmationTentative, models.LineupSelected, ErrUnavailable},
+		{models.ConfirmationTentative, models.LineupStandby, nil},
+		{models.ConfirmationTentative, models.LineupBench, nil},
+		{models.ConfirmationDeclined, models.LineupSelected, ErrUnavailable},
+		{models.ConfirmationDeclined, models.LineupStandby, ErrUnavailable},
+		{models.ConfirmationPending, models.LineupBench, ErrUnavailable},
+		{"", models.LineupStandby, ErrUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s as %s", cmpOr(tt.answer, "no answer"), tt.status), func(t *testing.T) {
+			f := newLineupFixture(t, "heroic")
+			id := f.character(t, "Thrall", tt.answer, true)
+
+			in := LineupInput{}
+			switch tt.status {
+			case models.LineupSelected:
+				in.Selected = []string{id}
+			case models.LineupStandby:
+				in.Standby = []string{id}
+			case models.LineupBench:
+				in.Bench = []string{id}
+			}
+			if _, err := f.save(in); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func cmpOr(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
+
+func TestSaveLineupRejectsInvalidCharacters(t *testing.T) {
+	f := newLineupFixture(t, "heroic")
+	id := f.character(t, "Thrall", models.ConfirmationConfirmed, true)
+
+	if _, err := f.save(LineupInput{Selected: []string{id}, Bench: []string{id}}); !errors.Is(err, ErrLineupDuplicate) {
+		t.Fatalf("listed twice: error = %v, want %v", err, ErrLineupDuplicate)
+	}
+	if _, err := f.save(LineupInput{Selected: []string{id, id}}); !errors.Is(err, ErrLineupDuplicate) {
+		t.Fatalf("selected twice: error = %v, want %v", err, ErrLineupDuplicate)
+	}
+
+	other := newLineupFixture(t, "heroic")
+	foreign := other.character(t, "Jaina", models.ConfirmationConfirmed, true)
+	if _, err := f.save(LineupInput{Selected: []string{foreign}}); !errors.Is(err, ErrForeignCharacter) {
+		t.Fatalf("character of another guild: error = %v, want %v", err, ErrForeignCharacter)
+	}
+}
+
+func TestSaveLineupVersion(t *testing.T) {
+	f := newLineupFixture(t, "heroic")
+	thrall := f.character(t, "Thrall", models.ConfirmationConfirmed, true)
+	garrosh := f.character(t, "Garrosh", models.ConfirmationConfirmed, true)
+	version := func(v int) *int { return &v }
+
+	lineup, err := f.save(LineupInput{Version: version(0), Selected: []string{thrall}})
+	if err != nil || lineup.Version != 1 {
+		t.Fatalf("first save: version %v, error %v; want version 1", lineup, err)
+	}
+	// Saving the same lineup changes nothing.
+	if lineup, err = f.save(LineupInput{Version: version(1), Selected: []string{thrall}}); err != nil || lineup.Version != 1 {
+		t.Fatalf("unchanged save: lineup %v, error %v; want version 1", lineup, err)
+	}
+	if _, err := f.save(LineupInput{Version: version(0), Selected: []string{garrosh}}); !errors.Is(err, ErrStaleLineup) {
+		t.Fatalf("save of an old version: error = %v, want %v", err, ErrStaleLineup)
+	}
+	// Without a version, the last save wins.
+	if lineup, err = f.save(LineupInput{Selected: []string{garrosh}}); err != nil || lineup.Version != 2 {
+		t.Fatalf("save without version: lineup %v, error %v; want version 2", lineup, err)
+	}
+
+	// A concurrent save of the same version makes the repository conflict.
+	racing := &LineupService{store: racingStore{f.store}}
+	_, err = racing.Save(context.Background(), f.event, f.officer.ID, LineupInput{Selected: []string{thrall}})
+	if !errors.Is(err, ErrStaleLineup) {
+		t.Fatalf("racing save: error = %v, want %v", err, ErrStaleLineup)
+	}
+}
+
+// racingStore loses every lineup save to a concurrent one.
+type racingStore struct {
+	repository.Store
+}
+
+func (s racingStore) Lineups() repository.LineupRepository {
+	return racingLineups{s.Store.Lineups()}
+}
+
+func (s racingStore) Transaction(ctx context.Context, fn func(tx repository.Store) error) error {
+	return s.Store.Transaction(ctx, func(tx repository.Store) error { return fn(racingStore{tx}) })
+}
+
+type racingLineups struct {
+	repository.LineupRepository
+}
+
+func (racingLineups) Save(context.Context, *models.Lineup) error {
+	return repository.ErrConflict
+}
+
+func TestLineupLock(t *testing.T) {
+	ctx := context.Background()
+	f := newLineupFixture(t, "mythic")
+	thrall := f.character(t, "Thrall", models.ConfirmationConfirmed, true)
+	garrosh := f.character(t, "Garrosh", models.ConfirmationConfirmed, true)
+	jaina := f.character(t, "Jaina", models.ConfirmationTentative, true)
+	nobody := f.character(t, "Ownerless", models.ConfirmationConfirmed, false)
+
+	if _, err := f.svc.Lineups.Lock(ctx, f.event, f.officer.ID); !errors.Is(err, ErrEmptyLineup) {
+		t.Fatalf("lock of an empty lineup: error = %v, want %v", err, ErrEmptyLineup)
+	}
+	if _, err := f.save(LineupInput{Selected: []string{thrall, garrosh, nobody}, Standby: []string{jaina}}); err != nil {
+		t.Fatal(err)
+	}
+	// Nobody hears about the lineup before it is locked.
+	if got := f.notifications(t, "Thrall"); len(got) != 0 {
+		t.Fatalf("notified before the lock: %v", got)
+	}
+
+	lineup, err := f.svc.Lineups.Lock(ctx, f.event, f.officer.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lineup.LockedAt == nil || lineup.Version != 2 {
+		t.Fatalf("locked lineup = %+v, want locked version 2", lineup)
+	}
+	if _, err := f.svc.Lineups.Lock(ctx, f.event, f.officer.ID); !errors.Is(err, ErrLineupLocked) {
+		t.Fatalf("second lock: error = %v, want %v", err, ErrLineupLocked)
+	}
+
+	// Locking tells the owners of the selected characters only.
+	selected := "Thrall was selected for Nerub-ar Palace (mythic) on Tue 20 Oct 19:00 UTC."
+	if got := f.notifications(t, "Thrall"); !slices.Equal(got, []string{selected}) {
+		t.Fatalf("Thrall's notifications = %q, want %q", got, selected)
+	}
+	if got := f.notifications(t, "Jaina"); len(got) != 0 {
+		t.Fatalf("standby character notified of the lock: %v", got)
+	}
+
+	// Later changes are flagged and only their characters' owners hear
+	// about them.
+	if _, err := f.save(LineupInput{Selected: []string{thrall, jaina, nobody}}); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("selecting a tentative character after the lock: error = %v, want %v", err, ErrUnavailable)
+	}
+	if _, err := f.save(LineupInput{Selected: []string{thrall, nobody}, Bench: []string{garrosh}}); err != nil {
+		t.Fatal(err)
+	}
+	wantMessages := map[string][]string{
+		"Thrall":  {selected},
+		"Garrosh": {"Garrosh was benched for Nerub-ar Palace (mythic) on Tue 20 Oct 19:00 UTC.", "Garrosh was selected for Nerub-ar Palace (mythic) on Tue 20 Oct 19:00 UTC."},
+		"Jaina":   {"Jaina was removed from the lineup of Nerub-ar Palace (mythic) on Tue 20 Oct 19:00 UTC."},
+	}
+	for name, want := range wantMessages {
+		got := f.notifications(t, name)
+		slices.Sort(got)
+		if !slices.Equal(got, want) {
+			t.Errorf("%s's notifications = %q, want %q", name, got, want)
+		}
+	}
+
+	history, err := f.svc.Lineups.History(ctx, f.event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type entry struct {
+		Action, Character, From, To string
+		Version                     int
+		AfterLock                   bool
+	}
+	var got []entry
+	for _, c := range history {
+		got = append(got, entry{c.Action, c.CharacterName, c.FromStatus, c.ToStatus, c.Version, c.AfterLock})
+	}
+	want := []entry{
+		{models.LineupActionUpdate, "Garrosh", "", models.LineupSelected, 1, false},
+		{models.LineupActionUpdate, "Jaina", "", models.LineupStandby, 1, false},
+		{models.LineupActionUpdate, "Ownerless", "", models.LineupSelected, 1, false},
+		{models.LineupActionUpdate, "Thrall", "", models.LineupSelected, 1, false},
+		{models.LineupActionLock, "", "", "", 2, false},
+		{models.LineupActionUpdate, "Garrosh", models.LineupSelected, models.LineupBench, 3, true},
+		{models.LineupActionUpdate, "Jaina", models.LineupStandby, "", 3, true},
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("history =\n%+v\nwant\n%+v", got, want)
+	}
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+)
+
+// NotificationService serves the notifications of users.
+type NotificationService struct {
+	store repository.Store
+}
+
+// List lists the user's notifications, newest first, only unread ones when
+// unreadOnly is set.
+func (s *NotificationService) List(ctx context.Context, userID string, unreadOnly bool, page repository.Page) ([]models.Notification, error) {
+	return s.store.Notifications().List(ctx, userID, unreadOnly, page)
+}
+
+// MarkRead marks one of the user's notifications as read.
+func (s *NotificationService) MarkRead(ctx context.Context, userID, id string) (*models.Notification, error) {
+	return s.store.Notifications().MarkRead(ctx, userID, id, time.Now().UTC())
+}
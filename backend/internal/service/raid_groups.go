@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+)
+
+// RaidGroupInput is what clients may set on a raid group. When CharacterIDs
+// is non-nil it replaces the group's membership.
+type RaidGroupInput struct {
+	Name         string
+	Schedule     *models.RaidSchedule
+	CharacterIDs *[]string
+}
+
+// RaidGroupService manages raid groups, their members and the events
+// generated from their schedules.
+type RaidGroupService struct {
+	store     repository.Store
+	generator EventGenerator
+}
+
+func (s *RaidGroupService) List(ctx context.Context, guildID string, page repository.Page) ([]models.RaidGroup, error) {
+	return s.store.RaidGroups().List(ctx, guildID, page)
+}
+
+// Get loads a raid group of the guild, with its characters when
+// withCharacters is set.
+func (s *RaidGroupService) Get(ctx context.Context, guildID, id string, withCharacters bool) (*models.RaidGroup, error) {
+	return s.store.RaidGroups().Get(ctx, guildID, id, withCharacters)
+}
+
+// Create validates the schedule, creates the group and generates its
+// upcoming events. Invalid schedules are reported as *models.ScheduleError.
+func (s *RaidGroupService) Create(ctx context.Context, guildID string, in RaidGroupInput) (*models.RaidGroup, error) {
+	if err := validateSchedule(in.Schedule); err != nil {
+		return nil, err
+	}
+
+	group := models.RaidGroup{Name: in.Name, GuildID: guildID, Schedule: in.Schedule}
+	err := s.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := tx.RaidGroups().Create(ctx, &group); err != nil {
+			return err
+		}
+		if err := s.replaceCharacters(ctx, tx, &group, in.CharacterIDs); err != nil {
+			return err
+		}
+		return s.generateEvents(ctx, tx, &group)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// Update replaces the group's name and schedule, and its characters when
+// given, then generates events the new schedule is missing.
+func (s *RaidGroupService) Update(ctx context.Context, group *models.RaidGroup, in RaidGroupInput) error {
+	if err := validateSchedule(in.Schedule); err != nil {
+		return err
+	}
+
+	return s.store.Transaction(ctx, func(tx repository.Store) error {
+		updated := *group
+		updated.Name, updated.Schedule = in.Name, in.Schedule
+		if err := tx.RaidGroups().Update(ctx, &updated); err != nil {
+			return err
+		}
+		if err := s.replaceCharacters(ctx, tx, &updated, in.CharacterIDs); err != nil {
+			return err
+		}
+		if err := s.generateEvents(ctx, tx, &updated); err != nil {
+			return err
+		}
+		*group = updated
+		return nil
+	})
+}
+
+func (s *RaidGroupService) Delete(ctx context.Context, group *models.RaidGroup) error {
+	return s.store.RaidGroups().Delete(ctx, group)
+}
+
+// replaceCharacters sets the group's characters when ids is non-nil. Every
+// character must belong to the group's guild, and new members get pending
+// confirmations for the group's upcoming events.
+func (s *RaidGroupService) replaceCharacters(ctx context.Context, tx repository.Store, group *models.RaidGroup, ids *[]string) error {
+	if ids == nil {
+		return nil
+	}
+
+	characters, err := tx.Characters().GetMany(ctx, group.GuildID, *ids)
+	if err != nil {
+		return err
+	}
+	if len(characters) != len(*ids) {
+		return ErrForeignCharacter
+	}
+	if err := tx.RaidGroups().SetCharacters(ctx, group, characters); err != nil {
+		return err
+	}
+	return tx.Confirmations().CreatePendingForGroup(ctx, group.ID, time.Now().UTC())
+}
+
+// generateEvents materializes the group's scheduled events right away instead
+// of waiting for the next generator run.
+func (s *RaidGroupService) generateEvents(ctx context.Context, tx repository.Store, group *models.RaidGroup) error {
+	if s.generator == nil {
+		return nil
+	}
+	_, err := s.generator.GenerateGroup(ctx, tx, group, time.Now().UTC())
+	return err
+}
+
+// validateSchedule returns the schedule's *models.ScheduleError, if any.
+func validateSchedule(schedule *models.RaidSchedule) error {
+	if schedule == nil {
+		return nil
+	}
+	return schedule.Validate()
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+)
+
+// RealmService lists the realms guilds and characters were created on.
+type RealmService struct {
+	store repository.Store
+}
+
+// List lists realms by region and name, only those of region unless it is
+// empty.
+func (s *RealmService) List(ctx context.Context, region string, page repository.Page) ([]models.Realm, error) {
+	return s.store.Realms().List(ctx, region, page)
+}
@@ -0,0 +1,76 @@
+// Package service holds the business rules of the guild manager on top of
+// the repositories, and decides where transactions begin and end. Handlers
+// call services instead of touching storage directly.
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+)
+
+// Errors for requests that break a business rule.
+var (
+	ErrLastGuildMaster  = errors.New("a guild needs at least one guild master")
+	ErrForeignCharacter = errors.New("character belongs to another guild")
+	ErrForeignRaidGroup = errors.New("raid group belongs to another guild")
+	ErrForeignUser      = errors.New("user is not a member of the guild")
+	ErrUnplayableRole   = errors.New("class cannot play this role")
+	ErrUnknownOffSpec   = errors.New("off-spec is not a spec of the class")
+	ErrStaleLineup      = errors.New("lineup was changed since it was loaded")
+	ErrLineupLocked     = errors.New("lineup is already locked")
+	ErrEmptyLineup      = errors.New("lineup has no selected character")
+	ErrLineupFull       = errors.New("too many selected characters")
+	ErrLineupDuplicate  = errors.New("character is listed more than once")
+	ErrUnavailable      = errors.New("character is not available")
+	ErrEventNotStarted  = errors.New("attendance can only be recorded once the event has started")
+)
+
+// EventGenerator materializes a raid group's scheduled events.
+type EventGenerator interface {
+	GenerateGroup(ctx context.Context, store repository.Store, group *models.RaidGroup, now time.Time) (int, error)
+}
+
+// Options configures the services.
+type Options struct {
+	// Region is the Battle.net region of realms created without one.
+	Region string
+	// RSVPCutoff is how long before an event RSVP changes are flagged late.
+	RSVPCutoff time.Duration
+	// Generator creates events when a raid group's schedule changes. When
+	// nil, they are left to the next background run.
+	Generator EventGenerator
+}
+
+// Services groups the services of every entity.
+type Services struct {
+	Users         *UserService
+	Realms        *RealmService
+	Guilds        *GuildService
+	Characters    *CharacterService
+	RaidGroups    *RaidGroupService
+	Events        *EventService
+	Confirmations *ConfirmationService
+	Attendance    *AttendanceService
+	Lineups       *LineupService
+	Notifications *NotificationService
+}
+
+// New creates the services on top of store.
+func New(store repository.Store, opts Options) *Services {
+	return &Services{
+		Users:         &UserService{store: store},
+		Realms:        &RealmService{store: store},
+		Guilds:        &GuildService{store: store, region: opts.Region},
+		Characters:    &CharacterService{store: store},
+		RaidGroups:    &RaidGroupService{store: store, generator: opts.Generator},
+		Events:        &EventService{store: store},
+		Confirmations: &ConfirmationService{store: store, cutoff: opts.RSVPCutoff},
+		Attendance:    &AttendanceService{store: store},
+		Lineups:       &LineupService{store: store},
+		Notifications: &NotificationService{store: store},
+	}
+}
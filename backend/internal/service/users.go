@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/calendar"
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+)
+
+// UserService manages users and the secrets of their calendar feeds.
+type UserService struct {
+	store repository.Store
+}
+
+func (s *UserService) Get(ctx context.Context, id string) (*models.User, error) {
+	return s.store.Users().Get(ctx, id)
+}
+
+// ByFeedToken returns the user whose calendar feeds use token.
+func (s *UserService) ByFeedToken(ctx context.Context, token string) (*models.User, error) {
+	return s.store.Users().GetByFeedToken(ctx, token)
+}
+
+// EnsureFeedToken gives the user a feed token unless they have one.
+func (s *UserService) EnsureFeedToken(ctx context.Context, user *models.User) error {
+	if user.FeedToken != nil {
+		return nil
+	}
+	return s.RotateFeedToken(ctx, user)
+}
+
+// RotateFeedToken replaces the user's feed token, invalidating every feed URL
+// handed out before.
+func (s *UserService) RotateFeedToken(ctx context.Context, user *models.User) error {
+	token, err := calendar.NewFeedToken()
+	if err != nil {
+		return err
+	}
+	if err := s.store.Users().SetFeedToken(ctx, user.ID, token); err != nil {
+		return err
+	}
+	user.FeedToken = &token
+	return nil
+}
+
+// RSVPs lists the answers of the user's characters to eventIDs.
+func (s *UserService) RSVPs(ctx context.Context, userID string, eventIDs []string) ([]repository.CharacterRSVP, error) {
+	return s.store.Confirmations().ForUser(ctx, userID, eventIDs)
+}
@@ -0,0 +1,264 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+
+	"github.com/GFerreiroS/guild-manager/backend/internal/metrics"
+	"github.com/GFerreiroS/guild-manager/backend/internal/models"
+	"github.com/GFerreiroS/guild-manager/backend/internal/repository"
+	"github.com/GFerreiroS/guild-manager/backend/internal/roster"
+	"github.com/GFerreiroS/guild-manager/backend/pkg/blizzard"
+	"github.com/GFerreiroS/guild-manager/backend/pkg/redis"
+)
+
+const (
+	lockKey = "lock:character-sync"
+	lockTTL = 5 * time.Minute
+)
+
+// ErrSyncRunning is returned when another replica is already running a cycle.
+var ErrSyncRunning = errors.New("a character sync is already running")
+
+// Failure describes a character that could not be refreshed.
+type Failure struct {
+	CharacterID string `json:"character_id"`
+	Name        string `json:"name"`
+	Realm       string `json:"realm"`
+	Error       string `json:"error"`
+}
+
+// Result summarizes a sync cycle.
+type Result struct {
+	Synced   int       `json:"synced"`
+	Failed   int       `json:"failed"`
+	Failures []Failure `json:"failures"`
+	// RostersReconciled counts guild rosters refreshed by a scheduled cycle;
+	// Roster holds the roster diff of a single-guild sync.
+	RostersReconciled int          `json:"rosters_reconciled,omitempty"`
+	Roster            *roster.Diff `json:"roster,omitempty"`
+}
+
+// Syncer refreshes character data from the Blizzard profile API.
+type Syncer struct {
+	store     repository.Store
+	rdb       *goredis.Client
+	client    *blizzard.Client
+	roster    *roster.Importer
+	staleAge  time.Duration
+	tick      time.Duration
+	batchSize int
+}
+
+// New creates a Syncer. Guild rosters and characters not synced within
+// staleAge are refreshed every tick, characters batchSize at a time.
+func New(store repository.Store, rdb *goredis.Client, client *blizzard.Client, importer *roster.Importer, staleAge, tick time.Duration, batchSize int) *Syncer {
+	return &Syncer{
+		store:     store,
+		rdb:       rdb,
+		client:    client,
+		roster:    importer,
+		staleAge:  staleAge,
+		tick:      tick,
+		batchSize: batchSize,
+	}
+}
+
+// Run syncs stale characters every tick until ctx is cancelled.
+func (s *Syncer) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.tick)
+	defer ticker.Stop()
+
+	for {
+		start := time.Now()
+		result, err := s.SyncStale(ctx)
+		switch {
+		case errors.Is(err, ErrSyncRunning):
+			slog.InfoContext(ctx, "character sync skipped: another replica holds the lock")
+			metrics.ObserveSync(metrics.SyncSkipped, 0, 0, 0)
+		case err != nil:
+			slog.ErrorContext(ctx, "character sync failed", "error", err)
+			synced, failed := 0, 0
+			if result != nil {
+				synced, failed = result.Synced, result.Failed
+			}
+			metrics.ObserveSync(metrics.SyncFailure, synced, failed, time.Since(start))
+		default:
+			slog.InfoContext(ctx, "character sync finished", "synced", result.Synced, "failed", result.Failed)
+			metrics.ObserveSync(metrics.SyncSuccess, result.Synced, result.Failed, time.Since(start))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SyncStale reconciles stale guild rosters, then refreshes every character
+// whose last sync is older than the configured stale age, oldest first.
+func (s *Syncer) SyncStale(ctx context.Context) (*Result, error) {
+	return s.withLock(ctx, func(lock *redis.Lock) (*Result, error) {
+		cutoff := time.Now().Add(-s.staleAge)
+		reconciled, err := s.reconcileRosters(ctx, lock, cutoff)
+		if err != nil {
+			return nil, err
+		}
+
+		ids, err := s.store.Characters().ListForSync(ctx, repository.SyncFilter{SyncedBefore: cutoff})
+		if err != nil {
+			return nil, fmt.Errorf("failed to select stale characters: %w", err)
+		}
+		result, err := s.syncIDs(ctx, lock, ids)
+		result.RostersReconciled = reconciled
+		return result, err
+	})
+}
+
+// SyncGuild reconciles a guild's roster and refreshes all of its characters
+// regardless of age.
+func (s *Syncer) SyncGuild(ctx context.Context, guildID string) (*Result, error) {
+	return s.withLock(ctx, func(lock *redis.Lock) (*Result, error) {
+		guild, err := s.store.Guilds().Get(ctx, guildID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load guild: %w", err)
+		}
+		diff, err := s.roster.Reconcile(ctx, guild)
+		if err != nil {
+			return nil, err
+		}
+
+		ids, err := s.store.Characters().ListForSync(ctx, repository.SyncFilter{GuildID: guildID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to select guild characters: %w", err)
+		}
+		result, err := s.syncIDs(ctx, lock, ids)
+		result.Roster = diff
+		return result, err
+	})
+}
+
+// reconcileRosters refreshes the roster of every guild not reconciled since
+// cutoff. A guild whose roster cannot be fetched is logged and skipped.
+func (s *Syncer) reconcileRosters(ctx context.Context, lock *redis.Lock, cutoff time.Time) (int, error) {
+	guilds, err := s.store.Guilds().ListStale(ctx, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to select stale guilds: %w", err)
+	}
+
+	reconciled := 0
+	for i := range guilds {
+		if ctx.Err() != nil {
+			return reconciled, ctx.Err()
+		}
+		diff, err := s.roster.Reconcile(ctx, &guilds[i])
+		if err != nil {
+			slog.ErrorContext(ctx, "roster reconciliation failed", "guild_id", guilds[i].ID, "error", err)
+			continue
+		}
+		slog.InfoContext(ctx, "reconciled roster", "guild", guilds[i].Name, "realm", guilds[i].Realm,
+			"added", len(diff.Added), "removed", len(diff.Removed), "changed", len(diff.Changed))
+		reconciled++
+
+		if err := lock.Refresh(ctx, lockTTL); err != nil {
+			return reconciled, fmt.Errorf("lost sync lock: %w", err)
+		}
+	}
+	return reconciled, nil
+}
+
+// withLock runs fn while holding the cluster-wide sync lock.
+func (s *Syncer) withLock(ctx context.Context, fn func(lock *redis.Lock) (*Result, error)) (*Result, error) {
+	lock, err := redis.ObtainLock(ctx, s.rdb, lockKey, lockTTL)
+	if errors.Is(err, redis.ErrLockHeld) {
+		return nil, ErrSyncRunning
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		// Release even if ctx was cancelled mid-cycle.
+		if err := lock.Release(context.Background()); err != nil {
+			slog.Error("failed to release sync lock", "error", err)
+		}
+	}()
+
+	return fn(lock)
+}
+
+// syncIDs refreshes the given characters in batches, extending the lock
+// before each character and stopping if it was lost. A failing character is
+// recorded and the batch goes on.
+func (s *Syncer) syncIDs(ctx context.Context, lock *redis.Lock, ids []string) (*Result, error) {
+	result := &Result{Failures: []Failure{}}
+
+	for start := 0; start < len(ids); start += s.batchSize {
+		end := min(start+s.batchSize, len(ids))
+
+		characters, err := s.store.Characters().GetByIDs(ctx, ids[start:end])
+		if err != nil {
+			return result, fmt.Errorf("failed to load characters: %w", err)
+		}
+
+		for i := range characters {
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			// A character can take several retried requests, so a batch may
+			// outlast the lock; extend it before each one.
+			if err := lock.Refresh(ctx, lockTTL); err != nil {
+				return result, fmt.Errorf("lost sync lock: %w", err)
+			}
+			if err := s.syncCharacter(ctx, &characters[i]); err != nil {
+				result.Failed++
+				result.Failures = append(result.Failures, Failure{
+					CharacterID: characters[i].ID,
+					Name:        characters[i].Name,
+					Realm:       characters[i].Realm,
+					Error:       err.Error(),
+				})
+				continue
+			}
+			result.Synced++
+		}
+	}
+
+	return result, nil
+}
+
+// syncCharacter refreshes one character from its profile. Failures are
+// stored on the row so officers can see why a character is out of date.
+func (s *Syncer) syncCharacter(ctx context.Context, character *models.Character) error {
+	profile, err := s.client.CharacterProfile(ctx, character.Realm, character.Name)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.recordFailure(ctx, character.ID, err)
+		}
+		return err
+	}
+
+	character.Ilvl = profile.EquippedItemLevel
+	character.Spec = profile.ActiveSpec.Name
+	character.LastSynced = time.Now().UTC()
+	character.SyncError = ""
+	if class := blizzard.ClassSlug(profile.CharacterClass.ID); class != "" {
+		character.Class = class
+	}
+
+	if err := s.store.Characters().SaveSync(ctx, character); err != nil {
+		return fmt.Errorf("failed to save character: %w", err)
+	}
+	return nil
+}
+
+func (s *Syncer) recordFailure(ctx context.Context, characterID string, syncErr error) {
+	if err := s.store.Characters().SetSyncError(ctx, characterID, syncErr.Error()); err != nil {
+		slog.ErrorContext(ctx, "failed to record sync error", "character_id", characterID, "error", err)
+	}
+}
@@ -0,0 +1,79 @@
+// Package web holds the HTML templates the server renders for HTMX, embedded
+// in the binary.
+package web
+
+import (
+	"embed"
+	"html/template"
+	"io/fs"
+	"strings"
+	"time"
+)
+
+//go:embed templates
+var files embed.FS
+
+// timeLayout formats times shown to users.
+const timeLayout = "Mon 2 Jan 2006 15:04 MST"
+
+// Funcs are the functions available to every template.
+var Funcs = template.FuncMap{
+	// datetime formats a time.Time or *time.Time, showing "never" for nil
+	// and zero times.
+	"datetime": func(v any) string {
+		var t time.Time
+		switch v := v.(type) {
+		case time.Time:
+			t = v
+		case *time.Time:
+			if v != nil {
+				t = *v
+			}
+		}
+		if t.IsZero() {
+			return "never"
+		}
+		return t.Format(timeLayout)
+	},
+	// date formats the day of a time, e.g. "Tue 5 Nov".
+	"date": func(t time.Time) string { return t.Format("Mon 2 Jan") },
+	// clock formats the time of day, e.g. "20:00".
+	"clock": func(t time.Time) string { return t.Format("15:04") },
+	// label turns identifiers such as "death-knight" into "Death Knight".
+	"label": label,
+}
+
+// acronyms are identifiers label keeps in upper case.
+var acronyms = map[string]string{"dps": "DPS"}
+
+func label(s string) string {
+	if a, ok := acronyms[s]; ok {
+		return a
+	}
+	words := strings.Fields(strings.ReplaceAll(s, "-", " "))
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// Templates parses every template below templates/, each named after its
+// path there, e.g. "partials/status.html".
+func Templates() (*template.Template, error) {
+	root := template.New("").Funcs(Funcs)
+	err := fs.WalkDir(files, "templates", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".html") {
+			return err
+		}
+		content, err := files.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		_, err = root.New(strings.TrimPrefix(path, "templates/")).Parse(string(content))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return root, nil
+}
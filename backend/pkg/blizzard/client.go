@@ -0,0 +1,331 @@
+package blizzard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// tokenExpiryMargin renews cached tokens slightly before they expire.
+const tokenExpiryMargin = time.Minute
+
+// NoRetries disables retries when set as Config.MaxRetries.
+const NoRetries = -1
+
+// Config holds the settings for a Client.
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	// Region is one of us, eu, kr, tw or cn.
+	Region string
+	// Locale defaults to en_US.
+	Locale string
+
+	// APIBaseURL and OAuthBaseURL override the region defaults, e.g. to
+	// point at an httptest server.
+	APIBaseURL   string
+	OAuthBaseURL string
+
+	// HTTPClient defaults to a client with a 15 second timeout.
+	HTTPClient *http.Client
+	// MaxRetries is the number of retries on 429 and 5xx responses,
+	// 3 when zero. Set it to NoRetries to disable retries.
+	MaxRetries int
+	// BaseBackoff is the first retry delay; it doubles on every attempt.
+	BaseBackoff time.Duration
+	// MaxBackoff caps retry delays, including those asked for by
+	// Retry-After. It defaults to 30 seconds.
+	MaxBackoff time.Duration
+}
+
+// Client talks to the Blizzard Game Data and Profile APIs.
+type Client struct {
+	cfg        Config
+	httpClient *http.Client
+	apiBase    string
+	oauthBase  string
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+	// tokenFetch coalesces concurrent token requests into one.
+	tokenFetch singleflight.Group
+}
+
+// APIError is returned for non-2xx responses.
+type APIError struct {
+	StatusCode int
+	URL        string
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("blizzard api %s returned status %d", e.URL, e.StatusCode)
+}
+
+// IsNotFound reports whether err is a 404 from the API.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// OAuthHost returns the Battle.net OAuth host for the given region.
+func OAuthHost(region string) string {
+	if strings.EqualFold(region, "cn") {
+		return "https://oauth.battlenet.com.cn"
+	}
+	return "https://oauth.battle.net"
+}
+
+// APIHost returns the Game Data/Profile API host for the given region.
+func APIHost(region string) string {
+	region = strings.ToLower(region)
+	if region == "cn" {
+		return "https://gateway.battlenet.com.cn"
+	}
+	return "https://" + region + ".api.blizzard.com"
+}
+
+// NewClient creates a Client.
+func NewClient(cfg Config) *Client {
+	cfg.Region = strings.ToLower(cfg.Region)
+	if cfg.Region == "" {
+		cfg.Region = "eu"
+	}
+	if cfg.Locale == "" {
+		cfg.Locale = "en_US"
+	}
+	switch {
+	case cfg.MaxRetries == 0:
+		cfg.MaxRetries = 3
+	case cfg.MaxRetries < 0:
+		cfg.MaxRetries = 0
+	}
+	if cfg.BaseBackoff == 0 {
+		cfg.BaseBackoff = 500 * time.Millisecond
+	}
+	if cfg.MaxBackoff == 0 {
+		cfg.MaxBackoff = 30 * time.Second
+	}
+
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 15 * time.Second}
+	}
+
+	apiBase := strings.TrimRight(cfg.APIBaseURL, "/")
+	if apiBase == "" {
+		apiBase = APIHost(cfg.Region)
+	}
+	oauthBase := strings.TrimRight(cfg.OAuthBaseURL, "/")
+	if oauthBase == "" {
+		oauthBase = OAuthHost(cfg.Region)
+	}
+
+	return &Client{
+		cfg:        cfg,
+		httpClient: httpClient,
+		apiBase:    apiBase,
+		oauthBase:  oauthBase,
+	}
+}
+
+// Region returns the region the client queries.
+func (c *Client) Region() string {
+	return c.cfg.Region
+}
+
+// accessToken returns a cached client-credentials token, fetching a new one
+// when the cached token is missing or about to expire. Concurrent callers
+// share a single fetch, which no caller's cancellation aborts.
+func (c *Client) accessToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	token, expiry := c.token, c.tokenExpiry
+	c.mu.Unlock()
+	if token != "" && time.Now().Before(expiry) {
+		return token, nil
+	}
+
+	result := c.tokenFetch.DoChan("token", func() (interface{}, error) {
+		return c.fetchToken(context.WithoutCancel(ctx))
+	})
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case r := <-result:
+		if r.Err != nil {
+			return "", r.Err
+		}
+		return r.Val.(string), nil
+	}
+}
+
+// fetchToken requests a client-credentials token and caches it.
+func (c *Client) fetchToken(ctx context.Context) (string, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.oauthBase+"/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to build token request: %w", err)
+	}
+	req.SetBasicAuth(c.cfg.ClientID, c.cfg.ClientSecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", &APIError{StatusCode: resp.StatusCode, URL: req.URL.String(), Body: string(body)}
+	}
+
+	var payload struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return "", fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if payload.AccessToken == "" {
+		return "", fmt.Errorf("token response is missing access_token")
+	}
+
+	c.mu.Lock()
+	c.token = payload.AccessToken
+	c.tokenExpiry = time.Now().Add(time.Duration(payload.ExpiresIn)*time.Second - tokenExpiryMargin)
+	c.mu.Unlock()
+	return payload.AccessToken, nil
+}
+
+// invalidateToken drops the cached token, e.g. after a 401.
+func (c *Client) invalidateToken() {
+	c.mu.Lock()
+	c.token = ""
+	c.mu.Unlock()
+}
+
+// get performs an authenticated GET against the API and decodes the JSON
+// response into out. 429 and 5xx responses are retried with exponential
+// backoff, honouring Retry-After when present, up to MaxBackoff.
+func (c *Client) get(ctx context.Context, path, namespace string, out interface{}) error {
+	query := url.Values{
+		"namespace": {namespace + "-" + c.cfg.Region},
+		"locale":    {c.cfg.Locale},
+	}
+	endpoint := c.apiBase + path + "?" + query.Encode()
+
+	var lastErr error
+	for attempt := 0; attempt <= c.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			if err := sleep(ctx, c.backoff(attempt, lastErr)); err != nil {
+				return err
+			}
+		}
+
+		token, err := c.accessToken(ctx)
+		if err != nil {
+			return err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return fmt.Errorf("failed to build request: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			lastErr = fmt.Errorf("request to %s failed: %w", path, err)
+			continue
+		}
+
+		if resp.StatusCode == http.StatusOK {
+			err := json.NewDecoder(resp.Body).Decode(out)
+			resp.Body.Close()
+			if err != nil {
+				return fmt.Errorf("failed to decode %s response: %w", path, err)
+			}
+			return nil
+		}
+
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		apiErr := &retryableError{
+			APIError:   &APIError{StatusCode: resp.StatusCode, URL: path, Body: string(body)},
+			retryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusUnauthorized:
+			// The token may have been revoked early; fetch a fresh one.
+			c.invalidateToken()
+			lastErr = apiErr
+		case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+			lastErr = apiErr
+		default:
+			return apiErr.APIError
+		}
+	}
+
+	var retryErr *retryableError
+	if errors.As(lastErr, &retryErr) {
+		return retryErr.APIError
+	}
+	return lastErr
+}
+
+// retryableError carries the server's Retry-After hint alongside the error.
+type retryableError struct {
+	*APIError
+	retryAfter time.Duration
+}
+
+func (c *Client) backoff(attempt int, lastErr error) time.Duration {
+	var retryErr *retryableError
+	if errors.As(lastErr, &retryErr) && retryErr.retryAfter > 0 {
+		return min(retryErr.retryAfter, c.cfg.MaxBackoff)
+	}
+	delay := c.cfg.BaseBackoff << (attempt - 1)
+	jitter := time.Duration(rand.Int63n(int64(delay)/2 + 1))
+	return min(delay+jitter, c.cfg.MaxBackoff)
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at)
+	}
+	return 0
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,178 @@
+package blizzard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeAPI serves the token endpoint and the realm resource. Realm requests
+// are answered with the statuses in order, then with 200.
+type fakeAPI struct {
+	server *httptest.Server
+
+	tokens   atomic.Int32
+	requests atomic.Int32
+	// tokenDelay holds token responses back, to overlap concurrent fetches.
+	tokenDelay time.Duration
+	retryAfter string
+
+	mu       sync.Mutex
+	statuses []int
+}
+
+func newFakeAPI(t *testing.T, statuses ...int) *fakeAPI {
+	t.Helper()
+
+	f := &fakeAPI{statuses: statuses}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		if id, secret, ok := r.BasicAuth(); !ok || id != "id" || secret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		f.tokens.Add(1)
+		time.Sleep(f.tokenDelay)
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "token", "expires_in": 3600})
+	})
+	mux.HandleFunc("GET /data/wow/realm/{slug}", func(w http.ResponseWriter, r *http.Request) {
+		f.requests.Add(1)
+		if r.Header.Get("Authorization") != "Bearer token" || r.URL.Query().Get("namespace") != "dynamic-eu" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		f.mu.Lock()
+		status := http.StatusOK
+		if len(f.statuses) > 0 {
+			status, f.statuses = f.statuses[0], f.statuses[1:]
+		}
+		f.mu.Unlock()
+
+		if status != http.StatusOK {
+			if f.retryAfter != "" {
+				w.Header().Set("Retry-After", f.retryAfter)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": 1, "name": "Silvermoon", "slug": r.PathValue("slug")})
+	})
+	f.server = httptest.NewServer(mux)
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeAPI) client(maxRetries int) *Client {
+	return NewClient(Config{
+		ClientID:     "id",
+		ClientSecret: "secret",
+		Region:       "EU",
+		APIBaseURL:   f.server.URL,
+		OAuthBaseURL: f.server.URL,
+		MaxRetries:   maxRetries,
+		BaseBackoff:  time.Millisecond,
+		MaxBackoff:   10 * time.Millisecond,
+	})
+}
+
+func TestClientCachesToken(t *testing.T) {
+	f := newFakeAPI(t)
+	f.tokenDelay = 20 * time.Millisecond
+	c := f.client(0)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Realm(context.Background(), "Silvermoon"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if _, err := c.Realm(context.Background(), "Silvermoon"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := f.tokens.Load(); got != 1 {
+		t.Fatalf("fetched %d tokens, want 1", got)
+	}
+}
+
+func TestClientRefreshesTokenAfterUnauthorized(t *testing.T) {
+	f := newFakeAPI(t, http.StatusUnauthorized)
+	c := f.client(0)
+
+	if _, err := c.Realm(context.Background(), "Silvermoon"); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.tokens.Load(); got != 2 {
+		t.Fatalf("fetched %d tokens, want 2", got)
+	}
+}
+
+func TestClientRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		maxRetries   int
+		wantErr      int
+		wantRequests int32
+	}{
+		{"429 then success", []int{http.StatusTooManyRequests}, 0, 0, 2},
+		{"5xx then success", []int{http.StatusBadGateway, http.StatusServiceUnavailable}, 0, 0, 3},
+		{"retries exhausted", []int{500, 500, 500}, 2, http.StatusInternalServerError, 3},
+		{"retries disabled", []int{http.StatusTooManyRequests}, NoRetries, http.StatusTooManyRequests, 1},
+		{"4xx not retried", []int{http.StatusForbidden}, 0, http.StatusForbidden, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeAPI(t, tt.statuses...)
+			_, err := f.client(tt.maxRetries).Realm(context.Background(), "Silvermoon")
+
+			var apiErr *APIError
+			switch {
+			case tt.wantErr == 0 && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.wantErr != 0 && (!errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantErr):
+				t.Fatalf("error = %v, want status %d", err, tt.wantErr)
+			}
+			if got := f.requests.Load(); got != tt.wantRequests {
+				t.Fatalf("sent %d requests, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestClientCapsRetryAfter(t *testing.T) {
+	f := newFakeAPI(t, http.StatusTooManyRequests)
+	f.retryAfter = "3600"
+
+	start := time.Now()
+	if _, err := f.client(0).Realm(context.Background(), "Silvermoon"); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("retry waited %s despite MaxBackoff", elapsed)
+	}
+}
+
+func TestClientNotFound(t *testing.T) {
+	f := newFakeAPI(t, http.StatusNotFound)
+
+	_, err := f.client(0).Realm(context.Background(), "Nowhere")
+	if !IsNotFound(err) {
+		t.Fatalf("IsNotFound(%v) = false", err)
+	}
+	if got := f.requests.Load(); got != 1 {
+		t.Fatalf("sent %d requests, want 1", got)
+	}
+}
@@ -0,0 +1,194 @@
+package blizzard
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Ref is a named reference to another API resource.
+type Ref struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// TypedName is an enum value such as a faction or an item slot.
+type TypedName struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// RealmRef identifies a realm.
+type RealmRef struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// GuildRef identifies a guild.
+type GuildRef struct {
+	ID      int64     `json:"id"`
+	Name    string    `json:"name"`
+	Realm   RealmRef  `json:"realm"`
+	Faction TypedName `json:"faction"`
+}
+
+// CharacterProfile is the character profile summary.
+type CharacterProfile struct {
+	ID                 int64     `json:"id"`
+	Name               string    `json:"name"`
+	Level              int       `json:"level"`
+	Faction            TypedName `json:"faction"`
+	CharacterClass     Ref       `json:"character_class"`
+	ActiveSpec         Ref       `json:"active_spec"`
+	Realm              RealmRef  `json:"realm"`
+	Guild              *GuildRef `json:"guild"`
+	AverageItemLevel   int       `json:"average_item_level"`
+	EquippedItemLevel  int       `json:"equipped_item_level"`
+	LastLoginTimestamp int64     `json:"last_login_timestamp"`
+}
+
+// EquippedItem is a single item from the equipment endpoint.
+type EquippedItem struct {
+	Item    Ref       `json:"item"`
+	Name    string    `json:"name"`
+	Slot    TypedName `json:"slot"`
+	Quality TypedName `json:"quality"`
+	Level   struct {
+		Value int `json:"value"`
+	} `json:"level"`
+}
+
+// CharacterEquipment is the character equipment summary.
+type CharacterEquipment struct {
+	EquippedItems []EquippedItem `json:"equipped_items"`
+}
+
+// CharacterSpecializations is the character specializations summary.
+type CharacterSpecializations struct {
+	ActiveSpecialization Ref `json:"active_specialization"`
+	Specializations      []struct {
+		Specialization Ref `json:"specialization"`
+	} `json:"specializations"`
+}
+
+// RosterCharacter is a character as listed in a guild roster.
+type RosterCharacter struct {
+	ID            int64    `json:"id"`
+	Name          string   `json:"name"`
+	Level         int      `json:"level"`
+	Realm         RealmRef `json:"realm"`
+	PlayableClass Ref      `json:"playable_class"`
+	PlayableRace  Ref      `json:"playable_race"`
+}
+
+// RosterMember is an entry of a guild roster.
+type RosterMember struct {
+	Character RosterCharacter `json:"character"`
+	Rank      int             `json:"rank"`
+}
+
+// AccountProfile is the WoW profile of a Battle.net account. Only the
+// account's own OAuth token may read it.
+type AccountProfile struct {
+	WowAccounts []struct {
+		ID         int64              `json:"id"`
+		Characters []AccountCharacter `json:"characters"`
+	} `json:"wow_accounts"`
+}
+
+// AccountCharacter is a character as listed in an account profile.
+type AccountCharacter struct {
+	ID    int64    `json:"id"`
+	Name  string   `json:"name"`
+	Level int      `json:"level"`
+	Realm RealmRef `json:"realm"`
+}
+
+// GuildRoster is the guild roster response.
+type GuildRoster struct {
+	Guild   GuildRef       `json:"guild"`
+	Members []RosterMember `json:"members"`
+}
+
+// classSlugs maps playable class IDs to the slugs allowed by the
+// characters.class CHECK constraint.
+var classSlugs = map[int64]string{
+	1:  "warrior",
+	2:  "paladin",
+	3:  "hunter",
+	4:  "rogue",
+	5:  "priest",
+	6:  "death-knight",
+	7:  "shaman",
+	8:  "mage",
+	9:  "warlock",
+	10: "monk",
+	11: "druid",
+	12: "demon-hunter",
+	13: "evoker",
+}
+
+// ClassSlug returns the class slug for a playable class ID, or "" if unknown.
+func ClassSlug(classID int64) string {
+	return classSlugs[classID]
+}
+
+// CharacterKey identifies a character within a region by its realm slug and
+// lowercased name, e.g. "kelthuzad/thrall".
+func CharacterKey(realm, name string) string {
+	return Slug(realm) + "/" + strings.ToLower(name)
+}
+
+// Slug converts a realm or guild name to the slug used in API paths,
+// e.g. "Kel'Thuzad" -> "kelthuzad" and "Aerie Peak" -> "aerie-peak".
+func Slug(name string) string {
+	s := strings.ToLower(strings.TrimSpace(name))
+	s = strings.ReplaceAll(s, "'", "")
+	return strings.Join(strings.Fields(s), "-")
+}
+
+func characterPath(realm, name string) string {
+	return fmt.Sprintf("/profile/wow/character/%s/%s",
+		url.PathEscape(Slug(realm)), url.PathEscape(strings.ToLower(name)))
+}
+
+// CharacterProfile fetches a character's profile summary.
+func (c *Client) CharacterProfile(ctx context.Context, realm, name string) (*CharacterProfile, error) {
+	var out CharacterProfile
+	if err := c.get(ctx, characterPath(realm, name), "profile", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CharacterEquipment fetches a character's equipped items.
+func (c *Client) CharacterEquipment(ctx context.Context, realm, name string) (*CharacterEquipment, error) {
+	var out CharacterEquipment
+	if err := c.get(ctx, characterPath(realm, name)+"/equipment", "profile", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CharacterSpecializations fetches a character's specializations.
+func (c *Client) CharacterSpecializations(ctx context.Context, realm, name string) (*CharacterSpecializations, error) {
+	var out CharacterSpecializations
+	if err := c.get(ctx, characterPath(realm, name)+"/specializations", "profile", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GuildRoster fetches the full roster of a guild.
+func (c *Client) GuildRoster(ctx context.Context, realm, guildName string) (*GuildRoster, error) {
+	path := fmt.Sprintf("/data/wow/guild/%s/%s/roster",
+		url.PathEscape(Slug(realm)), url.PathEscape(Slug(guildName)))
+
+	var out GuildRoster
+	if err := c.get(ctx, path, "profile", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
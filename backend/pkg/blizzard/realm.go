@@ -0,0 +1,42 @@
+package blizzard
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+)
+
+// Realm is the Game Data API realm resource.
+type Realm struct {
+	ID             int64  `json:"id"`
+	Name           string `json:"name"`
+	Slug           string `json:"slug"`
+	ConnectedRealm struct {
+		Href string `json:"href"`
+	} `json:"connected_realm"`
+}
+
+// ConnectedRealmID extracts the connected realm ID from its href, returning 0
+// when the href is missing or malformed.
+func (r *Realm) ConnectedRealmID() int64 {
+	u, err := url.Parse(r.ConnectedRealm.Href)
+	if err != nil {
+		return 0
+	}
+	id, err := strconv.ParseInt(path.Base(u.Path), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+// Realm fetches a realm by name or slug.
+func (c *Client) Realm(ctx context.Context, realm string) (*Realm, error) {
+	var out Realm
+	if err := c.get(ctx, fmt.Sprintf("/data/wow/realm/%s", url.PathEscape(Slug(realm))), "dynamic", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
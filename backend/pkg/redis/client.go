@@ -33,3 +33,8 @@ func NewClient(addr, password string, db int, timeout time.Duration) *Client {
 		Ctx:  context.Background(),
 	}
 }
+
+// Close closes the connection pool.
+func (c *Client) Close() error {
+	return c.Conn.Close()
+}
@@ -0,0 +1,77 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// ErrLockHeld is returned when another owner holds the lock.
+var ErrLockHeld = errors.New("lock is held by another owner")
+
+// Only the owner (matching token) may extend or release a lock.
+var (
+	refreshScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+	releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+// Lock is a single-owner distributed lock backed by a Redis key.
+type Lock struct {
+	rdb   *redis.Client
+	key   string
+	token string
+}
+
+// ObtainLock tries to acquire the lock at key for ttl. It returns
+// ErrLockHeld if someone else currently owns it.
+func ObtainLock(ctx context.Context, rdb *redis.Client, key string, ttl time.Duration) (*Lock, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to generate lock token: %w", err)
+	}
+	token := hex.EncodeToString(b)
+
+	ok, err := rdb.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lock %s: %w", key, err)
+	}
+	if !ok {
+		return nil, ErrLockHeld
+	}
+	return &Lock{rdb: rdb, key: key, token: token}, nil
+}
+
+// Refresh extends the lock's TTL. It returns ErrLockHeld if the lock expired
+// and was taken by someone else in the meantime.
+func (l *Lock) Refresh(ctx context.Context, ttl time.Duration) error {
+	n, err := refreshScript.Run(ctx, l.rdb, []string{l.key}, l.token, ttl.Milliseconds()).Int()
+	if err != nil {
+		return fmt.Errorf("failed to refresh lock %s: %w", l.key, err)
+	}
+	if n == 0 {
+		return ErrLockHeld
+	}
+	return nil
+}
+
+// Release frees the lock if it is still owned.
+func (l *Lock) Release(ctx context.Context) error {
+	if err := releaseScript.Run(ctx, l.rdb, []string{l.key}, l.token).Err(); err != nil {
+		return fmt.Errorf("failed to release lock %s: %w", l.key, err)
+	}
+	return nil
+}
@@ -7,7 +7,7 @@ max_retries=${MIGRATION_MAX_RETRIES:-3}
 until [ $attempt -gt $max_retries ]
 do
     echo "Running migrations (attempt $attempt/$max_retries)"
-    go run cmd/migrate/main.go -action up && break
+    go run cmd/migrate/main.go up && break
     
     attempt=$((attempt+1))
     sleep 5